@@ -1,28 +1,66 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"taskmaster/internal/app"
+	"taskmaster/internal/config"
 	"taskmaster/internal/storage"
 
 	"github.com/fatih/color"
 )
 
 func main() {
+	// Global options come before the command
+	args, dir, err := splitGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	os.Args = append(os.Args[:1], args...)
+
 	// Skip header if arguments are provided (i.e., we're running a command)
-	if len(os.Args) <= 1 {
+	// or if the interactive UI is about to take over the terminal
+	if len(os.Args) <= 1 && !app.Interactive() {
 		printHeader()
 	}
 
-	// Get target directory (current directory by default)
-	targetDir, err := os.Getwd()
+	// Get the current directory, where the workspace search starts
+	cwd, err := os.Getwd()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting current directory: %v\n", err)
 		os.Exit(1)
 	}
 
+	if dir == "" {
+		dir = os.Getenv(storage.EnvDir)
+	}
+	command := ""
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	// Find the workspace; init creates one instead, and commands that do
+	// not touch tasks run without one
+	var targetDir string
+	exists := false
+	if command == "init" {
+		targetDir, err = storage.WorkspaceRoot(firstNonEmpty(dir, cwd))
+	} else {
+		targetDir, err = storage.ResolveWorkspace(dir, cwd)
+		exists = err == nil
+		if errors.Is(err, storage.ErrNoWorkspace) && !app.RequiresWorkspace(args) {
+			targetDir, err = storage.WorkspaceRoot(firstNonEmpty(dir, cwd))
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create storage
 	store, err := storage.NewFileStorage(targetDir)
 	if err != nil {
@@ -32,22 +70,74 @@ func main() {
 	defer store.Close()
 
 	// Initialize storage
-	if err := store.Init(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
+	if exists {
+		if err := store.Init(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error initializing storage: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	// Load workspace configuration
+	cfg, err := config.Load(store.Dir())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading configuration: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Create app
 	taskApp := app.NewApp(store)
+	taskApp.SetConfig(cfg)
+	if exists {
+		taskApp.EnableWebhooks()
+	}
 	defer taskApp.Close()
 
 	// Run the CLI
 	if err := app.RunCLI(taskApp); err != nil {
+		// Plugins have reported their own failure
+		var exitErr *app.ExitError
+		if errors.As(err, &exitErr) {
+			taskApp.Close()
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// splitGlobalFlags removes the global --dir option from the front of the
+// arguments, returning the remaining arguments and the directory
+func splitGlobalFlags(args []string) ([]string, string, error) {
+	dir := ""
+	for len(args) > 0 {
+		arg := args[0]
+		switch {
+		case arg == "--dir" || arg == "-dir":
+			if len(args) < 2 {
+				return nil, "", errors.New("--dir requires a directory")
+			}
+			dir = args[1]
+			args = args[2:]
+		case strings.HasPrefix(arg, "--dir="), strings.HasPrefix(arg, "-dir="):
+			dir = arg[strings.Index(arg, "=")+1:]
+			args = args[1:]
+		default:
+			return args, dir, nil
+		}
+	}
+	return args, dir, nil
+}
+
+// firstNonEmpty returns the first of its arguments that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 // printHeader prints a colorful header for the app
 func printHeader() {
 	fmt.Println()
@@ -5,11 +5,11 @@ go 1.23.4
 require (
 	github.com/fatih/color v1.18.0
 	github.com/gookit/color v1.5.4
+	golang.org/x/sys v0.25.0
 )
 
 require (
 	github.com/mattn/go-colorable v0.1.13 // indirect
 	github.com/mattn/go-isatty v0.0.20 // indirect
 	github.com/xo/terminfo v0.0.0-20210125001918-ca9a967f8778 // indirect
-	golang.org/x/sys v0.25.0 // indirect
 )
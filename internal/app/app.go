@@ -1,22 +1,101 @@
 package app
 
 import (
-	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"taskmaster/internal/config"
 	"taskmaster/internal/models"
 	"taskmaster/internal/storage"
 )
 
 // App represents the core application that manages tasks
 type App struct {
-	storage storage.Storage
+	storage   storage.Storage
+	config    *config.Config
+	listeners []func(event string, task *models.Task)
+
+	// webhooksQueued records that this run queued a webhook delivery
+	webhooksQueued atomic.Bool
+
+	// metrics is set while serving metrics
+	metrics *appMetrics
+
+	// held collects post-hooks and task events while holding is set, so
+	// an all-or-nothing change reports nothing until it has succeeded
+	holding bool
+	held    []func()
+
+	// untrustedHooks warns once that hooks were skipped in an untrusted workspace
+	untrustedHooks sync.Once
+}
+
+// Task events reported to listeners registered with OnTaskEvent
+const (
+	EventCreated   = "created"
+	EventCompleted = "completed"
+	EventDeleted   = "deleted"
+	EventOverdue   = "overdue"
+)
+
+// ValidationError reports input that the application rejects, as opposed to
+// a failure to read or write tasks
+type ValidationError struct {
+	Message string
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// invalid returns a ValidationError with a formatted message
+func invalid(format string, args ...any) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
 }
 
 // NewApp creates a new application instance
 func NewApp(s storage.Storage) *App {
-	return &App{storage: s}
+	return &App{storage: s, config: config.Default()}
+}
+
+// SetConfig replaces the workspace configuration used by the application
+func (a *App) SetConfig(cfg *config.Config) {
+	a.config = cfg
+}
+
+// Config returns the workspace configuration
+func (a *App) Config() *config.Config {
+	return a.config
+}
+
+// Root returns the workspace directory, falling back to the current directory
+// for storage implementations that are not tied to one
+func (a *App) Root() string {
+	if r, ok := a.storage.(interface{ Root() string }); ok {
+		return r.Root()
+	}
+	cwd, _ := os.Getwd()
+	return cwd
+}
+
+// OnTaskEvent registers fn to be called after a task is created, completed
+// or deleted
+func (a *App) OnTaskEvent(fn func(event string, task *models.Task)) {
+	a.listeners = append(a.listeners, fn)
+}
+
+// emit reports an event to the registered listeners
+func (a *App) emit(event string, task *models.Task) {
+	a.notice(func() {
+		for _, fn := range a.listeners {
+			fn(event, task)
+		}
+	})
 }
 
 // Initialize initializes the application
@@ -24,15 +103,22 @@ func (a *App) Initialize() error {
 	return a.storage.Init()
 }
 
-// Close cleans up resources
+// Close delivers webhooks queued by this run and cleans up resources.
+// Read-only commands queue nothing, so they never wait on the network.
 func (a *App) Close() error {
+	if a.webhooksQueued.Load() {
+		a.flushWebhooks()
+	}
 	return a.storage.Close()
 }
 
 // CreateTask creates a new task
-func (a *App) CreateTask(title, desc string, dueDate time.Time, priority models.Priority) (*models.Task, error) {
+func (a *App) CreateTask(title, desc string, dueDate time.Time, priority models.Priority, tags []string) (*models.Task, error) {
 	if title == "" {
-		return nil, errors.New("task title cannot be empty")
+		return nil, invalid("task title cannot be empty")
+	}
+	if err := validatePriority(priority); err != nil {
+		return nil, err
 	}
 
 	task := &models.Task{
@@ -42,46 +128,284 @@ func (a *App) CreateTask(title, desc string, dueDate time.Time, priority models.
 		Priority:    priority,
 		Progress:    0,
 		Completed:   false,
+		Tags:        normalizeTags(tags),
 	}
 
+	if err := a.createTask(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// createTask stores a new task, running the add hooks around it. A creation
+// time already set on the task is kept.
+func (a *App) createTask(task *models.Task) error {
+	if err := a.preHook(hookAdd, task); err != nil {
+		return err
+	}
+	start := time.Now()
 	err := a.storage.CreateTask(task)
+	a.observe(storageCreate, start)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create task: %w", err)
+		return fmt.Errorf("failed to create task: %w", err)
 	}
 
-	return task, nil
+	a.postHook(hookAdd, task)
+	a.emit(EventCreated, task)
+	return nil
+}
+
+// Outcomes of importing a record, reported by ImportTask
+const (
+	ImportCreated = "created"
+	ImportUpdated = "updated"
+	ImportSkipped = "skipped"
+)
+
+// ImportTask stores a task read from another tool, keeping its tags, progress,
+// annotations, creation and modification times and completion state. A record
+// whose UUID or external ID matches an existing task updates that task instead
+// of creating a duplicate, unless the task was changed here more recently.
+func (a *App) ImportTask(record *models.Task) (*models.Task, string, error) {
+	if err := validateImport(record); err != nil {
+		return nil, "", err
+	}
+
+	existing, err := a.FindImported(record)
+	if err != nil {
+		return nil, "", err
+	}
+	if existing != nil {
+		if newerLocally(existing, record) {
+			return existing, ImportSkipped, nil
+		}
+
+		wasCompleted := existing.Completed
+		existing.Title = record.Title
+		existing.Description = record.Description
+		existing.DueDate = record.DueDate
+		existing.Priority = record.Priority
+		applyImportedFields(existing, record)
+
+		if err := a.updateTaskAt(changeAction(wasCompleted, existing), existing, record.UpdatedAt); err != nil {
+			return nil, "", fmt.Errorf("failed to update imported task: %w", err)
+		}
+		if existing.Completed && !wasCompleted {
+			a.emit(EventCompleted, existing)
+		}
+		return existing, ImportUpdated, nil
+	}
+
+	task := &models.Task{
+		UUID:        record.UUID,
+		ExternalID:  record.ExternalID,
+		Title:       record.Title,
+		Description: record.Description,
+		DueDate:     record.DueDate,
+		Priority:    record.Priority,
+		UpdatedAt:   record.UpdatedAt,
+	}
+	applyImportedFields(task, record)
+	if err := a.createTask(task); err != nil {
+		return nil, "", err
+	}
+	return task, ImportCreated, nil
+}
+
+// newerLocally reports whether a task was changed here after the imported
+// record of it was last modified, so importing the record would lose changes
+func newerLocally(task, record *models.Task) bool {
+	return !record.UpdatedAt.IsZero() && task.UpdatedAt.After(record.UpdatedAt)
+}
+
+// FindTaskByUUID returns the task with the given UUID, or nil if there is none
+func (a *App) FindTaskByUUID(uuid string) (*models.Task, error) {
+	tasks, err := a.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		if strings.EqualFold(task.UUID, uuid) {
+			return task, nil
+		}
+	}
+	return nil, nil
+}
+
+// FindImported returns the task an imported record was previously imported
+// as, matched by UUID or by external ID, or nil if there is none
+func (a *App) FindImported(record *models.Task) (*models.Task, error) {
+	if record.UUID != "" {
+		return a.FindTaskByUUID(record.UUID)
+	}
+	if record.ExternalID == "" {
+		return nil, nil
+	}
+
+	tasks, err := a.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+	for _, task := range tasks {
+		if task.ExternalID == record.ExternalID {
+			return task, nil
+		}
+	}
+	return nil, nil
+}
+
+// validateImport checks an imported record with the same rules as CreateTask
+func validateImport(record *models.Task) error {
+	if record.Title == "" {
+		return invalid("task title cannot be empty")
+	}
+	if err := validatePriority(record.Priority); err != nil {
+		return err
+	}
+	if record.UUID != "" && !models.ValidUUID(record.UUID) {
+		return invalid("invalid task UUID %q", record.UUID)
+	}
+	if record.Progress < 0 || record.Progress > 100 {
+		return invalid("progress must be between 0 and 100, got %d", record.Progress)
+	}
+	return nil
+}
+
+// applyImportedFields copies the fields CreateTask does not take from an imported record
+func applyImportedFields(task, record *models.Task) {
+	task.Tags = normalizeTags(record.Tags)
+	task.Annotations = record.Annotations
+	task.Progress = record.Progress
+	if !record.CreatedAt.IsZero() {
+		task.CreatedAt = record.CreatedAt
+	}
+
+	if record.Completed {
+		completedAt := record.CompletionTime()
+		if completedAt.IsZero() {
+			completedAt = time.Now()
+		}
+		task.MarkCompleted(completedAt)
+	} else {
+		task.MarkOpen()
+	}
 }
 
 // GetTask retrieves a task by ID
 func (a *App) GetTask(id int64) (*models.Task, error) {
+	defer a.observe(storageGet, time.Now())
 	return a.storage.GetTask(id)
 }
 
 // GetAllTasks retrieves all tasks
 func (a *App) GetAllTasks() ([]*models.Task, error) {
+	defer a.observe(storageGetAll, time.Now())
 	return a.storage.GetAllTasks()
 }
 
 // DeleteTask deletes a task
 func (a *App) DeleteTask(id int64) error {
-	return a.storage.DeleteTask(id)
+	task, err := a.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	if err := a.preHook(hookDelete, task); err != nil {
+		return err
+	}
+	start := time.Now()
+	err = a.storage.DeleteTask(id)
+	a.observe(storageDelete, start)
+	if err != nil {
+		return err
+	}
+	a.postHook(hookDelete, task)
+	a.emit(EventDeleted, task)
+	return nil
 }
 
 // CompleteTask marks a task as completed
 func (a *App) CompleteTask(id int64) error {
-	return a.storage.CompleteTask(id)
+	task, err := a.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	wasCompleted := task.Completed
+	task.MarkCompleted(time.Now())
+	if err := a.updateTask(hookComplete, task); err != nil {
+		return err
+	}
+	if task.Completed && !wasCompleted {
+		a.emit(EventCompleted, task)
+	}
+	return nil
+}
+
+// ReopenTask marks a completed task as open again with the given progress (0-99)
+func (a *App) ReopenTask(id int64, progress int) error {
+	if progress < 0 || progress > 99 {
+		return invalid("progress of a reopened task must be between 0 and 99, got %d", progress)
+	}
+
+	task, err := a.GetTask(id)
+	if err != nil {
+		return err
+	}
+	if !task.Completed {
+		return invalid("task %d is not completed", id)
+	}
+
+	task.MarkOpen()
+	task.Progress = progress
+	return a.updateTask(hookModify, task)
 }
 
 // UpdateTaskProgress updates the progress of a task
 func (a *App) UpdateTaskProgress(id int64, progress int) error {
 	if progress < 0 || progress > 100 {
-		return fmt.Errorf("progress must be between 0 and 100, got %d", progress)
+		return invalid("progress must be between 0 and 100, got %d", progress)
 	}
-	return a.storage.UpdateTaskProgress(id, progress)
+
+	task, err := a.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	// Progress of 100% completes the task, anything less reopens it
+	wasCompleted := task.Completed
+	task.Progress = progress
+	if progress == 100 {
+		task.MarkCompleted(time.Now())
+	} else {
+		task.MarkOpen()
+	}
+
+	if err := a.updateTask(changeAction(wasCompleted, task), task); err != nil {
+		return err
+	}
+	if task.Completed && !wasCompleted {
+		a.emit(EventCompleted, task)
+	}
+	return nil
 }
 
 // UpdateTaskDetails updates a task's details
 func (a *App) UpdateTaskDetails(id int64, title, desc string, dueDate time.Time, priority models.Priority) error {
+	return a.EditTask(id, title, desc, dueDate, priority, nil)
+}
+
+// EditTask updates a task's details and, unless tags is nil, replaces its
+// tags, saving both in a single modification
+func (a *App) EditTask(id int64, title, desc string, dueDate time.Time, priority models.Priority, tags *[]string) error {
+	if title == "" {
+		return invalid("task title cannot be empty")
+	}
+	if err := validatePriority(priority); err != nil {
+		return err
+	}
+
 	task, err := a.GetTask(id)
 	if err != nil {
 		return err
@@ -92,6 +416,48 @@ func (a *App) UpdateTaskDetails(id int64, title, desc string, dueDate time.Time,
 	task.Description = desc
 	task.DueDate = dueDate
 	task.Priority = priority
+	if tags != nil {
+		task.Tags = normalizeTags(*tags)
+	}
 
-	return a.storage.UpdateTask(task)
+	return a.updateTask(hookModify, task)
+}
+
+// AnnotateTask adds a timestamped note to a task
+func (a *App) AnnotateTask(id int64, note string) error {
+	if note == "" {
+		return invalid("annotation cannot be empty")
+	}
+
+	task, err := a.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	task.Annotations = append(task.Annotations, models.Annotation{Entry: time.Now(), Description: note})
+	return a.updateTask(hookModify, task)
+}
+
+// normalizeTags trims tags and drops empty and duplicate entries
+func normalizeTags(tags []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, tag)
+	}
+	return result
+}
+
+// validatePriority checks that a priority is one of the known levels
+func validatePriority(p models.Priority) error {
+	if p < models.Low || p > models.Critical {
+		return invalid("priority must be between %d and %d, got %d", models.Low, models.Critical, p)
+	}
+	return nil
 }
@@ -0,0 +1,237 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"taskmaster/internal/models"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// untaggedColumn is the board column holding tasks without tags
+const untaggedColumn = "Untagged"
+
+// boardColumn is a single column of the kanban board
+type boardColumn struct {
+	name  string
+	color *color.Color
+	tasks []*models.Task
+}
+
+// showBoard renders tasks as a kanban board grouped by status, priority or tag
+func showBoard(app *App, args []string) error {
+	boardCmd := flag.NewFlagSet("board", flag.ExitOnError)
+	byPtr := boardCmd.String("by", "status", "Group columns by: status, priority or tag")
+	widthPtr := boardCmd.Int("width", 0, "Board width in characters (defaults to the terminal width)")
+
+	if err := boardCmd.Parse(args); err != nil {
+		return err
+	}
+
+	tasks, err := app.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("error retrieving tasks: %w", err)
+	}
+
+	var columns []*boardColumn
+	switch *byPtr {
+	case "status":
+		columns = columnsByStatus(tasks)
+	case "priority":
+		columns = columnsByPriority(tasks)
+	case "tag", "tags":
+		columns = columnsByTag(tasks)
+	default:
+		return fmt.Errorf("invalid grouping %q: must be status, priority or tag", *byPtr)
+	}
+
+	if len(columns) == 0 {
+		fmt.Println("No tasks found.")
+		return nil
+	}
+
+	width := *widthPtr
+	if width <= 0 {
+		width = outputWidth()
+	}
+
+	renderBoard(columns, width, app.Config().Board.WIPLimit)
+	return nil
+}
+
+// columnsByStatus groups tasks into one column per status
+func columnsByStatus(tasks []*models.Task) []*boardColumn {
+	columns := []*boardColumn{}
+	for _, status := range []string{statusInProgress, statusOverdue, statusCompleted} {
+		columns = append(columns, &boardColumn{name: status, color: statusColor(status)})
+	}
+
+	for _, task := range tasks {
+		for _, column := range columns {
+			if column.name == statusName(task) {
+				column.tasks = append(column.tasks, task)
+			}
+		}
+	}
+
+	return columns
+}
+
+// columnsByPriority groups tasks into one column per priority, most important first
+func columnsByPriority(tasks []*models.Task) []*boardColumn {
+	columns := []*boardColumn{}
+	for p := models.Critical; p >= models.Low; p-- {
+		column := &boardColumn{name: p.String(), color: color.New(color.FgCyan)}
+		for _, task := range tasks {
+			if task.Priority == p {
+				column.tasks = append(column.tasks, task)
+			}
+		}
+		columns = append(columns, column)
+	}
+
+	return columns
+}
+
+// columnsByTag groups tasks into one column per tag; tasks with several tags appear in each
+func columnsByTag(tasks []*models.Task) []*boardColumn {
+	byName := make(map[string]*boardColumn)
+	var names []string
+	var untagged []*models.Task
+
+	for _, task := range tasks {
+		if len(task.Tags) == 0 {
+			untagged = append(untagged, task)
+			continue
+		}
+		for _, tag := range task.Tags {
+			key := strings.ToLower(tag)
+			column, ok := byName[key]
+			if !ok {
+				column = &boardColumn{name: tag, color: color.New(color.FgMagenta)}
+				byName[key] = column
+				names = append(names, key)
+			}
+			column.tasks = append(column.tasks, task)
+		}
+	}
+
+	sort.Strings(names)
+
+	var columns []*boardColumn
+	for _, name := range names {
+		columns = append(columns, byName[name])
+	}
+	if len(untagged) > 0 {
+		columns = append(columns, &boardColumn{name: untaggedColumn, color: color.New(color.FgWhite), tasks: untagged})
+	}
+
+	return columns
+}
+
+// Board layout: columns are separated by boardGap and never narrower than
+// minColumnWidth, unless the terminal cannot fit even one
+const (
+	boardGap       = " │ "
+	minColumnWidth = 16
+)
+
+// renderBoard prints the columns side by side within the given width. When
+// they do not all fit, the remaining columns continue on further rows.
+func renderBoard(columns []*boardColumn, width int, wipLimit func(string) (int, bool)) {
+	perRow, colWidth := boardLayout(len(columns), width)
+	for start := 0; start < len(columns); start += perRow {
+		if start > 0 {
+			fmt.Println()
+		}
+		renderBoardRow(columns[start:min(start+perRow, len(columns))], colWidth, wipLimit)
+	}
+}
+
+// boardLayout returns how many of n columns fit side by side within width,
+// and how wide each of them is
+func boardLayout(n, width int) (perRow, colWidth int) {
+	gapWidth := utf8.RuneCountInString(boardGap)
+	perRow = max(1, min(n, (width+gapWidth)/(minColumnWidth+gapWidth)))
+	colWidth = (width - gapWidth*(perRow-1)) / perRow
+	return perRow, max(colWidth, 1)
+}
+
+// renderBoardRow prints one row of columns, each colWidth wide
+func renderBoardRow(columns []*boardColumn, colWidth int, wipLimit func(string) (int, bool)) {
+	// Column headers with counts and WIP limits
+	var headers, rules []string
+	for _, column := range columns {
+		header := fmt.Sprintf("%s (%d)", strings.ToUpper(column.name), len(column.tasks))
+		style := column.color.Add(color.Bold)
+
+		if limit, ok := wipLimit(column.name); ok && limit > 0 {
+			header = fmt.Sprintf("%s (%d/%d)", strings.ToUpper(column.name), len(column.tasks), limit)
+			if len(column.tasks) > limit {
+				header += " !"
+				style = color.New(color.FgWhite, color.BgRed, color.Bold)
+			}
+		}
+
+		headers = append(headers, style.Sprint(fitString(header, colWidth)))
+		rules = append(rules, strings.Repeat("─", colWidth))
+	}
+	fmt.Println(strings.Join(headers, boardGap))
+	fmt.Println(strings.Join(rules, "─┼─"))
+
+	// Cards, three lines each
+	cards := make([][]string, len(columns))
+	rows := 0
+	for i, column := range columns {
+		for _, task := range column.tasks {
+			cards[i] = append(cards[i], boardCard(task, colWidth)...)
+		}
+		if len(cards[i]) > rows {
+			rows = len(cards[i])
+		}
+	}
+
+	for row := 0; row < rows; row++ {
+		var line []string
+		for i := range columns {
+			if row < len(cards[i]) {
+				line = append(line, cards[i][row])
+			} else {
+				line = append(line, strings.Repeat(" ", colWidth))
+			}
+		}
+		fmt.Println(strings.TrimRight(strings.Join(line, boardGap), " "))
+	}
+}
+
+// boardCard renders a task as a card of fixed width
+func boardCard(task *models.Task, width int) []string {
+	title := fitString(fmt.Sprintf("#%d %s", task.ID, task.Title), width)
+	if task.Completed {
+		title = color.New(color.Faint).Sprint(title)
+	}
+
+	details := fmt.Sprintf(" %3d%%", task.Progress)
+	if !task.DueDate.IsZero() {
+		details += " " + task.DueDate.Format("Jan 02")
+	}
+	meta := task.Priority.ColoredString() + fitString(details, width-len(task.Priority.String()))
+
+	return []string{title, meta, strings.Repeat(" ", width)}
+}
+
+// outputWidth returns the width of the terminal attached to stdout, or a sensible default
+func outputWidth() int {
+	if w, _, err := terminalSize(int(os.Stdout.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 80
+}
@@ -0,0 +1,28 @@
+package app
+
+import "testing"
+
+func TestBoardLayout(t *testing.T) {
+	tests := []struct {
+		name                 string
+		columns, width       int
+		wantPerRow, wantCols int
+	}{
+		{"wide terminal", 3, 120, 3, 38},
+		{"exact fit", 3, 3*16 + 2*3, 3, 16},
+		{"one short", 3, 3*16 + 2*3 - 1, 2, 25},
+		{"narrow terminal", 5, 40, 2, 18},
+		{"narrower than a column", 3, 10, 1, 10},
+		{"single column", 1, 200, 1, 200},
+	}
+	for _, tt := range tests {
+		perRow, colWidth := boardLayout(tt.columns, tt.width)
+		if perRow != tt.wantPerRow || colWidth != tt.wantCols {
+			t.Errorf("%s: boardLayout(%d, %d) = %d, %d; want %d, %d",
+				tt.name, tt.columns, tt.width, perRow, colWidth, tt.wantPerRow, tt.wantCols)
+		}
+		if used := perRow*colWidth + (perRow-1)*3; used > tt.width {
+			t.Errorf("%s: board is %d wide, more than %d", tt.name, used, tt.width)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"taskmaster/internal/models"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// dateKeyLayout is the layout used to compare due dates by calendar day
+const dateKeyLayout = "2006-01-02"
+
+// tasksByDay groups open and completed tasks with a due date by calendar day
+func tasksByDay(tasks []*models.Task) map[string][]*models.Task {
+	days := make(map[string][]*models.Task)
+	for _, task := range tasks {
+		if task.DueDate.IsZero() {
+			continue
+		}
+		key := task.DueDate.Format(dateKeyLayout)
+		days[key] = append(days[key], task)
+	}
+
+	for _, dayTasks := range days {
+		sort.Slice(dayTasks, func(i, j int) bool {
+			if dayTasks[i].Priority != dayTasks[j].Priority {
+				return dayTasks[i].Priority > dayTasks[j].Priority
+			}
+			return dayTasks[i].ID < dayTasks[j].ID
+		})
+	}
+
+	return days
+}
+
+// parseMonth parses a month argument as YYYY-MM, a month number or a month name
+func parseMonth(arg string, now time.Time) (time.Time, error) {
+	if arg == "" {
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local), nil
+	}
+
+	if t, err := time.ParseInLocation("2006-01", arg, time.Local); err == nil {
+		return t, nil
+	}
+
+	if n, err := strconv.Atoi(arg); err == nil {
+		if n < 1 || n > 12 {
+			return time.Time{}, fmt.Errorf("month must be between 1 and 12, got %d", n)
+		}
+		return time.Date(now.Year(), time.Month(n), 1, 0, 0, 0, 0, time.Local), nil
+	}
+
+	for m := time.January; m <= time.December; m++ {
+		name := strings.ToLower(m.String())
+		if lower := strings.ToLower(arg); len(lower) >= 3 && strings.HasPrefix(name, lower) {
+			return time.Date(now.Year(), m, 1, 0, 0, 0, 0, time.Local), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid month %q: use YYYY-MM, 1-12 or a month name", arg)
+}
+
+// showCalendar renders a month grid with the tasks due on each day
+func showCalendar(app *App, args []string) error {
+	var monthArg string
+	if len(args) > 0 {
+		monthArg = args[0]
+	}
+
+	now := time.Now()
+	month, err := parseMonth(monthArg, now)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := app.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("error retrieving tasks: %w", err)
+	}
+	days := tasksByDay(tasks)
+
+	cellWidth := (outputWidth() - 8) / 7
+	if cellWidth < 10 {
+		cellWidth = 10
+	}
+	const tasksPerCell = 3
+
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	faint := color.New(color.Faint).SprintFunc()
+	todayStyle := color.New(color.ReverseVideo, color.Bold).SprintFunc()
+
+	// Month title and weekday header, weeks start on Monday
+	fmt.Println(cyan(month.Format("January 2006")))
+	var header []string
+	for _, name := range []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"} {
+		header = append(header, cyan(fitString(name, cellWidth)))
+	}
+	fmt.Println(strings.Join(header, " "))
+	fmt.Println(strings.Repeat("─", cellWidth*7+6))
+
+	todayKey := now.Format(dateKeyLayout)
+	offset := (int(month.Weekday()) + 6) % 7
+	start := month.AddDate(0, 0, -offset)
+
+	for week := start; week.Before(month.AddDate(0, 1, 0)); week = week.AddDate(0, 0, 7) {
+		rows := make([][]string, tasksPerCell+1)
+
+		for d := 0; d < 7; d++ {
+			day := week.AddDate(0, 0, d)
+			key := day.Format(dateKeyLayout)
+			inMonth := day.Month() == month.Month()
+			weekend := day.Weekday() == time.Saturday || day.Weekday() == time.Sunday
+
+			// Day number line, with a task count when anything is due
+			label := fmt.Sprintf("%2d", day.Day())
+			if n := len(days[key]); n > 0 && inMonth {
+				label += fmt.Sprintf(" (%d)", n)
+			}
+			label = fitString(label, cellWidth)
+			switch {
+			case !inMonth:
+				label = strings.Repeat(" ", cellWidth)
+			case key == todayKey:
+				label = todayStyle(label)
+			case weekend:
+				label = faint(label)
+			}
+			rows[0] = append(rows[0], label)
+
+			// Task title lines
+			for i := 0; i < tasksPerCell; i++ {
+				line := strings.Repeat(" ", cellWidth)
+				dayTasks := days[key]
+				if inMonth && i < len(dayTasks) {
+					if i == tasksPerCell-1 && len(dayTasks) > tasksPerCell {
+						line = faint(fitString(fmt.Sprintf("+%d more", len(dayTasks)-i), cellWidth))
+					} else {
+						line = calendarEntry(dayTasks[i], cellWidth)
+					}
+				}
+				rows[i+1] = append(rows[i+1], line)
+			}
+		}
+
+		for _, row := range rows {
+			fmt.Println(strings.TrimRight(strings.Join(row, " "), " "))
+		}
+		fmt.Println(faint(strings.Repeat("─", cellWidth*7+6)))
+	}
+
+	return nil
+}
+
+// calendarEntry renders a task title colored by its status
+func calendarEntry(task *models.Task, width int) string {
+	text := fitString(fmt.Sprintf("#%d %s", task.ID, task.Title), width)
+	if task.Completed {
+		return color.New(color.Faint, color.CrossedOut).Sprint(text)
+	}
+	return statusColor(statusName(task)).Sprint(text)
+}
+
+// showAgenda lists overdue tasks and tasks due in the coming days, grouped by day
+func showAgenda(app *App, args []string) error {
+	agendaCmd := flag.NewFlagSet("agenda", flag.ExitOnError)
+	daysPtr := agendaCmd.Int("days", 7, "Number of days to show, starting today")
+
+	if err := agendaCmd.Parse(args); err != nil {
+		return err
+	}
+	if *daysPtr < 1 {
+		return errors.New("days must be at least 1")
+	}
+
+	tasks, err := app.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("error retrieving tasks: %w", err)
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	todayKey := today.Format(dateKeyLayout)
+
+	// Only open tasks belong on the agenda
+	var open []*models.Task
+	for _, task := range tasks {
+		if !task.Completed {
+			open = append(open, task)
+		}
+	}
+	days := tasksByDay(open)
+
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	red := color.New(color.FgRed, color.Bold).SprintFunc()
+	faint := color.New(color.Faint).SprintFunc()
+	todayStyle := color.New(color.ReverseVideo, color.Bold).SprintFunc()
+
+	fmt.Println(cyan(fmt.Sprintf("AGENDA — next %d days", *daysPtr)))
+	fmt.Println(strings.Repeat("-", 80))
+
+	// Overdue tasks come first, oldest due date first
+	var overdueKeys []string
+	for key := range days {
+		if key < todayKey {
+			overdueKeys = append(overdueKeys, key)
+		}
+	}
+	sort.Strings(overdueKeys)
+
+	if len(overdueKeys) > 0 {
+		fmt.Println(red("Overdue"))
+		for _, key := range overdueKeys {
+			for _, task := range days[key] {
+				printAgendaTask(task, faint(key))
+			}
+		}
+		fmt.Println()
+	}
+
+	empty := true
+	for i := 0; i < *daysPtr; i++ {
+		day := today.AddDate(0, 0, i)
+		key := day.Format(dateKeyLayout)
+		dayTasks := days[key]
+		if len(dayTasks) == 0 && key != todayKey {
+			continue
+		}
+
+		heading := day.Format("Monday, Jan 02")
+		switch {
+		case key == todayKey:
+			heading = todayStyle(" Today — " + heading + " ")
+		case day.Weekday() == time.Saturday || day.Weekday() == time.Sunday:
+			heading = faint(heading)
+		default:
+			heading = cyan(heading)
+		}
+		fmt.Println(heading)
+
+		if len(dayTasks) == 0 {
+			fmt.Println(faint("  Nothing due"))
+		}
+		for _, task := range dayTasks {
+			printAgendaTask(task, "")
+			empty = false
+		}
+		fmt.Println()
+	}
+
+	if empty && len(overdueKeys) == 0 {
+		fmt.Println("No upcoming deadlines found.")
+	}
+
+	return nil
+}
+
+// printAgendaTask prints a single agenda line
+func printAgendaTask(task *models.Task, prefix string) {
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	line := fmt.Sprintf("  %-5d %-30s %-10s %s",
+		task.ID,
+		truncateString(task.Title, 28),
+		task.Priority.String(),
+		yellow(fmt.Sprintf("%d%%", task.Progress)))
+	if prefix != "" {
+		line += "  " + prefix
+	}
+	fmt.Println(line)
+}
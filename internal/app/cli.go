@@ -16,38 +16,68 @@ import (
 
 // RunCLI runs the application in command-line interface mode
 func RunCLI(app *App) error {
-	// Define the available commands
-	commands := map[string]func([]string) error{
-		"list":      func(args []string) error { return listTasks(app) },
-		"create":    func(args []string) error { return createTask(app, args) },
-		"view":      func(args []string) error { return viewTask(app, args) },
-		"edit":      func(args []string) error { return editTask(app, args) },
-		"progress":  func(args []string) error { return updateProgress(app, args) },
-		"complete":  func(args []string) error { return completeTask(app, args) },
-		"delete":    func(args []string) error { return deleteTask(app, args) },
-		"due":       func(args []string) error { return showDeadlines(app) },
-		"help":      func(args []string) error { return showHelp() },
-		"deadlines": func(args []string) error { return showDeadlines(app) },
-	}
-
-	// Check if command is provided
+	// Without a command, open the interactive UI when attached to a terminal
 	if len(os.Args) < 2 {
+		if Interactive() {
+			return runTUI(app)
+		}
 		return showHelp()
 	}
 
 	cmd := os.Args[1]
 	args := os.Args[2:]
 
-	// Check if command exists
-	cmdFunc, exists := commands[cmd]
+	// Anything that is not a built-in command may be a plugin
+	cmdFunc, exists := commandTable(app)[cmd]
 	if !exists {
-		return fmt.Errorf("unknown command: %s\nRun 'taskmaster help' for usage", cmd)
+		return runPlugin(app, cmd, args)
 	}
 
 	// Execute the command
 	return cmdFunc(args)
 }
 
+// commandTable returns the built-in commands bound to an application
+func commandTable(app *App) map[string]func([]string) error {
+	return map[string]func([]string) error{
+		"list":         func(args []string) error { return listTasks(app, args) },
+		"create":       func(args []string) error { return createTask(app, args) },
+		"view":         func(args []string) error { return viewTask(app, args) },
+		"edit":         func(args []string) error { return editTask(app, args) },
+		"progress":     func(args []string) error { return updateProgress(app, args) },
+		"complete":     func(args []string) error { return completeTask(app, args) },
+		"reopen":       func(args []string) error { return reopenTask(app, args) },
+		"delete":       func(args []string) error { return deleteTask(app, args) },
+		"due":          func(args []string) error { return showDeadlines(app, args) },
+		"help":         func(args []string) error { return showHelp() },
+		"deadlines":    func(args []string) error { return showDeadlines(app, args) },
+		"tui":          func(args []string) error { return runTUI(app) },
+		"board":        func(args []string) error { return showBoard(app, args) },
+		"calendar":     func(args []string) error { return showCalendar(app, args) },
+		"agenda":       func(args []string) error { return showAgenda(app, args) },
+		"stats":        func(args []string) error { return showStats(app, args) },
+		"export":       func(args []string) error { return exportTasks(app, args) },
+		"import":       func(args []string) error { return importTasks(app, args) },
+		"report":       func(args []string) error { return generateReport(app, args) },
+		"scan":         func(args []string) error { return scanComments(app, args) },
+		"git-hook":     func(args []string) error { return gitHook(app, args) },
+		"hooks":        func(args []string) error { return listHooks(app, args) },
+		"serve":        func(args []string) error { return serve(app, args) },
+		"mcp":          func(args []string) error { return runMCP(app, args) },
+		"rpc":          func(args []string) error { return runRPC(app, args) },
+		"webhook":      func(args []string) error { return webhookCommand(app, args) },
+		"workspace":    func(args []string) error { return workspaceCommand(app, args) },
+		"init":         func(args []string) error { return initWorkspace(app, args) },
+		"reconcile":    func(args []string) error { return reconcileTasks(app, args) },
+		"migrate":      func(args []string) error { return migrateWorkspace(app, args) },
+		"merge-driver": func(args []string) error { return mergeDriver(app, args) },
+		"plugins":      func(args []string) error { return listPlugins(app, args) },
+		"daemon":       func(args []string) error { return runDaemon(app, args) },
+		"remind":       func(args []string) error { return remindCommand(app, args) },
+		"digest":       func(args []string) error { return digestCommand(app, args) },
+	}
+}
+
 // showHelp displays usage information
 func showHelp() error {
 	blue := color.New(color.FgBlue, color.Bold).SprintFunc()
@@ -63,25 +93,62 @@ func showHelp() error {
 
 	// Print usage
 	fmt.Println(yellow("USAGE:"))
-	fmt.Println("  taskmaster [command] [arguments]")
+	fmt.Println("  taskmaster [--dir PATH] [command] [arguments]")
 	fmt.Println()
 
 	// Print available commands
 	fmt.Println(yellow("COMMANDS:"))
-	fmt.Println("  " + green("list") + "                    List all tasks")
+	fmt.Println("  " + green("init") + "                    Create a workspace in the current directory")
+	fmt.Println("  " + green("list") + " [--all-workspaces]  List all tasks")
 	fmt.Println("  " + green("create") + "                  Create a new task")
-	fmt.Printf("    %s --title \"Task Title\" [--desc \"Description\"] [--due YYYY-MM-DD] [--priority 0-3]\n",
+	fmt.Printf("    %s --title \"Task Title\" [--desc \"Description\"] [--due YYYY-MM-DD] [--priority 0-3] [--tags a,b]\n",
 		green("    taskmaster create"))
 	fmt.Println("  " + green("view") + " [id]               View details of a task")
 	fmt.Println("  " + green("edit") + " [id]               Edit a task")
-	fmt.Printf("    %s --title \"New Title\" [--desc \"New Description\"] [--due YYYY-MM-DD] [--priority 0-3]\n",
+	fmt.Printf("    %s --title \"New Title\" [--desc \"New Description\"] [--due YYYY-MM-DD] [--priority 0-3] [--tags a,b]\n",
 		green("    taskmaster edit [id]"))
 	fmt.Println("  " + green("progress") + " [id] [value]   Update task progress (0-100)")
 	fmt.Println("  " + green("complete") + " [id]          Mark a task as complete")
+	fmt.Println("  " + green("reopen") + " [id] [progress] Reopen a completed task (progress defaults to 0)")
 	fmt.Println("  " + green("delete") + " [id]            Delete a task")
+	fmt.Println("  " + green("remind") + " [id] [when]     Remind about a task at a time (\"in 2h\", \"tomorrow 9:00\"); list, cancel [id]")
+	fmt.Println("  " + green("daemon") + " [--once]        Send due-date and requested reminders as they fall due")
+	fmt.Println("  " + green("board") + " [--by status|priority|tag]  Show tasks as a kanban board")
+	fmt.Println("  " + green("calendar") + " [month]       Show a month calendar of due dates (YYYY-MM, 1-12 or name)")
+	fmt.Println("  " + green("agenda") + " [--days N]      Show overdue and upcoming tasks grouped by day")
+	fmt.Println("  " + green("stats") + " [--output json]  Show workspace statistics")
+	fmt.Println("  " + green("export") + " --format F [--output file]     Export tasks (todotxt, taskwarrior, ics)")
+	fmt.Println("  " + green("import") + " --format F [--dry-run] file    Import tasks (todotxt, taskwarrior, ics, csv)")
+	fmt.Println("  " + green("report") + " --format markdown|html [--template file]  Generate a status report")
+	fmt.Println("  " + green("digest") + " send [--period daily|weekly] [--dry-run]  Email overdue, upcoming and completed tasks")
+	fmt.Println("  " + green("scan") + " [paths] [--dry-run]  Sync tasks with TODO/FIXME/HACK comments")
+	fmt.Println("  " + green("git-hook") + " install|uninstall  Update tasks from commit messages ('fixes #12', 'progress #12 60%')")
+	fmt.Println("  " + green("hooks") + " list             Show the lifecycle hook scripts in .taskmaster/hooks")
+	fmt.Println("  " + green("merge-driver") + " install   Merge task files field by field when git branches meet")
+	fmt.Println("  " + green("reconcile") + "             Renumber tasks that share an ID after a merge")
+	fmt.Println("  " + green("migrate") + "                 Upgrade task files written by older versions")
+	fmt.Println("  " + green("webhook") + " list|deliver|test|receive  Inspect and send webhooks for task events")
+	fmt.Println("  " + green("plugins") + " list           Show the taskmaster-<name> plugins that add commands")
+	fmt.Println("  " + green("workspace") + " add|list|remove  Manage the workspaces shown by --all-workspaces")
+	fmt.Println("  " + green("workspace") + " trust|untrust  Allow or stop this workspace's hooks and plugins")
+	fmt.Println("  " + green("serve") + " [--addr host:port]  Serve a web interface and JSON REST API")
+	fmt.Println("  " + green("rpc") + "                    Serve JSON-RPC 2.0 on stdin/stdout for editor integrations")
+	fmt.Println("  " + green("mcp") + "                    Serve the Model Context Protocol on stdin/stdout for AI assistants")
+	fmt.Println("  " + green("tui") + "                    Open the interactive terminal UI")
 	fmt.Println("  " + green("help") + "                   Show this help message")
 	fmt.Println()
 
+	// Print workspace lookup
+	fmt.Println(yellow("WORKSPACE:"))
+	fmt.Println("  Commands use the nearest .taskmaster directory in the current directory or its parents.")
+	fmt.Println("  Use --dir PATH or the TASKMASTER_DIR environment variable to choose one explicitly.")
+	fmt.Println()
+
+	// Print plugin lookup
+	fmt.Println(yellow("PLUGINS:"))
+	fmt.Println("  Other commands run a taskmaster-<command> executable from .taskmaster/plugins, once the workspace is trusted, or your PATH.")
+	fmt.Println()
+
 	// Print priority levels
 	fmt.Println(yellow("PRIORITY LEVELS:"))
 	fmt.Println("  0 - Low")
@@ -98,16 +165,23 @@ func showHelp() error {
 	fmt.Println("  taskmaster complete 2")
 
 	// Print available commands (add these lines to the existing commands list)
-	fmt.Println("  " + green("deadlines") + " / " + green("due") + "      Show upcoming deadlines")
+	fmt.Println("  " + green("deadlines") + " / " + green("due") + " [--all-workspaces]  Show upcoming deadlines")
 
 	return nil
 }
 
 // listTasks lists all tasks
-func listTasks(app *App) error {
-	tasks, err := app.GetAllTasks()
+func listTasks(app *App, args []string) error {
+	listCmd := flag.NewFlagSet("list", flag.ExitOnError)
+	allPtr := listCmd.Bool(allWorkspacesFlag, false, "List tasks from every registered workspace")
+
+	if err := listCmd.Parse(args); err != nil {
+		return err
+	}
+
+	tasks, err := collectTasks(app, *allPtr)
 	if err != nil {
-		return fmt.Errorf("error retrieving tasks: %w", err)
+		return err
 	}
 
 	if len(tasks) == 0 {
@@ -120,14 +194,16 @@ func listTasks(app *App) error {
 	yellow := color.New(color.FgYellow).SprintFunc()
 
 	// Print table header
-	fmt.Printf("%-5s %-30s %-10s %-10s %s\n",
+	width := idWidth(tasks)
+	fmt.Printf("%-*s %-30s %-10s %-10s %s\n", width,
 		cyan("ID"), cyan("TITLE"), cyan("PRIORITY"), cyan("PROGRESS"), cyan("STATUS"))
 	fmt.Println(strings.Repeat("-", 80))
 
 	// Print each task
-	for _, task := range tasks {
-		fmt.Printf("%-5d %-30s %-10s %-10s %s\n",
-			task.ID,
+	for _, row := range tasks {
+		task := row.Task
+		fmt.Printf("%-*s %-30s %-10s %-10s %s\n", width,
+			row.ID,
 			truncateString(task.Title, 28),
 			task.Priority.String(),
 			yellow(fmt.Sprintf("%d%%", task.Progress)),
@@ -145,6 +221,7 @@ func createTask(app *App, args []string) error {
 	descPtr := createCmd.String("desc", "", "Task description")
 	duePtr := createCmd.String("due", "", "Due date (YYYY-MM-DD)")
 	priorityPtr := createCmd.Int("priority", 1, "Priority (0-3): 0=Low, 1=Medium, 2=High, 3=Critical")
+	tagsPtr := createCmd.String("tags", "", "Comma-separated list of tags")
 
 	// Parse flags
 	err := createCmd.Parse(args)
@@ -174,7 +251,7 @@ func createTask(app *App, args []string) error {
 	priority := models.Priority(*priorityPtr)
 
 	// Create the task
-	task, err := app.CreateTask(*titlePtr, *descPtr, dueDate, priority)
+	task, err := app.CreateTask(*titlePtr, *descPtr, dueDate, priority, parseTags(*tagsPtr))
 	if err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
@@ -216,10 +293,28 @@ func viewTask(app *App, args []string) error {
 
 	fmt.Printf("%s: %s\n", bold("Priority"), task.Priority.String())
 	fmt.Printf("%s: %d%%\n", bold("Progress"), task.Progress)
+	if len(task.Tags) > 0 {
+		fmt.Printf("%s: %s\n", bold("Tags"), strings.Join(task.Tags, ", "))
+	}
+	if task.Source != nil {
+		fmt.Printf("%s: %s (%s)\n", bold("Source"), task.Source.Location(), task.Source.Kind)
+	}
 	fmt.Printf("%s: %v\n", bold("Completed"), task.Completed)
+	if task.Completed && !task.CompletionTime().IsZero() {
+		fmt.Printf("%s: %s\n", bold("Completed At"), task.CompletionTime().Format("2006-01-02 15:04:05"))
+	}
 	fmt.Printf("%s: %s\n", bold("Created At"), task.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("%s: %s\n", bold("Updated At"), task.UpdatedAt.Format("2006-01-02 15:04:05"))
 
+	if len(task.Annotations) > 0 {
+		fmt.Printf("%s:\n", bold("Annotations"))
+		for _, annotation := range task.Annotations {
+			fmt.Printf("  %s  %s\n", annotation.Entry.Format("2006-01-02 15:04"), annotation.Description)
+		}
+	}
+
+	printTaskCommits(app, task.ID)
+
 	return nil
 }
 
@@ -252,6 +347,7 @@ func editTask(app *App, args []string) error {
 	duePtr := editCmd.String("due", defaultDue, "Due date (YYYY-MM-DD)")
 
 	priorityPtr := editCmd.Int("priority", int(task.Priority), "Priority (0-3): 0=Low, 1=Medium, 2=High, 3=Critical")
+	tagsPtr := editCmd.String("tags", strings.Join(task.Tags, ","), "Comma-separated list of tags")
 
 	// Parse flags, excluding the first argument which is the task ID
 	err = editCmd.Parse(args[1:])
@@ -277,8 +373,17 @@ func editTask(app *App, args []string) error {
 	}
 	priority := models.Priority(*priorityPtr)
 
+	// Only replace the tags when asked to
+	var tags *[]string
+	editCmd.Visit(func(f *flag.Flag) {
+		if f.Name == "tags" {
+			tagList := parseTags(*tagsPtr)
+			tags = &tagList
+		}
+	})
+
 	// Update the task
-	err = app.UpdateTaskDetails(id, *titlePtr, *descPtr, dueDate, priority)
+	err = app.EditTask(id, *titlePtr, *descPtr, dueDate, priority, tags)
 	if err != nil {
 		return fmt.Errorf("failed to update task: %w", err)
 	}
@@ -336,6 +441,34 @@ func completeTask(app *App, args []string) error {
 	return nil
 }
 
+// reopenTask marks a completed task as open again
+func reopenTask(app *App, args []string) error {
+	if len(args) < 1 {
+		return errors.New("task ID is required")
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid task ID: %w", err)
+	}
+
+	progress := 0
+	if len(args) > 1 {
+		progress, err = strconv.Atoi(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid progress value: %w", err)
+		}
+	}
+
+	err = app.ReopenTask(id, progress)
+	if err != nil {
+		return fmt.Errorf("failed to reopen task: %w", err)
+	}
+
+	fmt.Printf("Task %d reopened at %d%%\n", id, progress)
+	return nil
+}
+
 // deleteTask deletes a task
 func deleteTask(app *App, args []string) error {
 	if len(args) < 1 {
@@ -372,6 +505,52 @@ func deleteTask(app *App, args []string) error {
 	return nil
 }
 
+// parseInterspersed parses flags that may appear before or after positional
+// arguments, as in "import file.csv --map title=Summary"
+func parseInterspersed(fs *flag.FlagSet, args []string) error {
+	var flags, positional []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			positional = append(positional, args[i+1:]...)
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' {
+			positional = append(positional, arg)
+			continue
+		}
+
+		flags = append(flags, arg)
+
+		// Flags given as "--name value" take the next argument along with them
+		name := strings.TrimLeft(arg, "-")
+		if strings.Contains(name, "=") {
+			continue
+		}
+		if f := fs.Lookup(name); f != nil && !isBoolFlag(f) && i+1 < len(args) {
+			flags = append(flags, args[i+1])
+			i++
+		}
+	}
+
+	return fs.Parse(append(append(flags, "--"), positional...))
+}
+
+// isBoolFlag reports whether a flag can be given without a value
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// parseTags splits a comma-separated list of tags
+func parseTags(s string) []string {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
 // Helper functions (reused from tui_simple.go)
 func truncateString(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -380,32 +559,60 @@ func truncateString(s string, maxLen int) string {
 	return s[:maxLen-3] + "..."
 }
 
-func getStatusText(task *models.Task) string {
-	green := color.New(color.FgGreen).SprintFunc()
-	blue := color.New(color.FgBlue).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
+// Plain status labels used in listings
+const (
+	statusInProgress = "In Progress"
+	statusOverdue    = "Overdue"
+	statusCompleted  = "Completed"
+)
 
+// statusName returns the plain status label of a task
+func statusName(task *models.Task) string {
 	if task.Completed {
-		return green("Completed")
+		return statusCompleted
 	}
 	if !task.DueDate.IsZero() && time.Now().After(task.DueDate) {
-		return red("Overdue")
+		return statusOverdue
+	}
+	return statusInProgress
+}
+
+// statusColor returns the color used to display a status label
+func statusColor(status string) *color.Color {
+	switch status {
+	case statusCompleted:
+		return color.New(color.FgGreen)
+	case statusOverdue:
+		return color.New(color.FgRed)
+	default:
+		return color.New(color.FgBlue)
 	}
-	return blue("In Progress")
+}
+
+func getStatusText(task *models.Task) string {
+	status := statusName(task)
+	return statusColor(status).Sprint(status)
 }
 
 // showDeadlines displays upcoming task deadlines
-func showDeadlines(app *App) error {
-	tasks, err := app.GetAllTasks()
+func showDeadlines(app *App, args []string) error {
+	dueCmd := flag.NewFlagSet("due", flag.ExitOnError)
+	allPtr := dueCmd.Bool(allWorkspacesFlag, false, "Show deadlines from every registered workspace")
+
+	if err := dueCmd.Parse(args); err != nil {
+		return err
+	}
+
+	tasks, err := collectTasks(app, *allPtr)
 	if err != nil {
-		return fmt.Errorf("error retrieving tasks: %w", err)
+		return err
 	}
 
 	// Filter tasks with deadlines that aren't completed
-	var tasksWithDeadlines []*models.Task
-	for _, task := range tasks {
-		if !task.DueDate.IsZero() && !task.Completed {
-			tasksWithDeadlines = append(tasksWithDeadlines, task)
+	var tasksWithDeadlines []workspaceTask
+	for _, row := range tasks {
+		if !row.Task.DueDate.IsZero() && !row.Task.Completed {
+			tasksWithDeadlines = append(tasksWithDeadlines, row)
 		}
 	}
 
@@ -415,8 +622,8 @@ func showDeadlines(app *App) error {
 	}
 
 	// Sort by deadline (already sorted by the database, but just to be sure)
-	sort.Slice(tasksWithDeadlines, func(i, j int) bool {
-		return tasksWithDeadlines[i].DueDate.Before(tasksWithDeadlines[j].DueDate)
+	sort.SliceStable(tasksWithDeadlines, func(i, j int) bool {
+		return tasksWithDeadlines[i].Task.DueDate.Before(tasksWithDeadlines[j].Task.DueDate)
 	})
 
 	// Define colors
@@ -428,13 +635,15 @@ func showDeadlines(app *App) error {
 	// Print header
 	fmt.Println(cyan("UPCOMING DEADLINES"))
 	fmt.Println(strings.Repeat("-", 80))
-	fmt.Printf("%-5s %-30s %-12s %-15s %s\n",
+	width := idWidth(tasksWithDeadlines)
+	fmt.Printf("%-*s %-30s %-12s %-15s %s\n", width,
 		cyan("ID"), cyan("TITLE"), cyan("PRIORITY"), cyan("DUE DATE"), cyan("DAYS LEFT"))
 	fmt.Println(strings.Repeat("-", 80))
 
 	// Print each task with deadline
 	now := time.Now()
-	for _, task := range tasksWithDeadlines {
+	for _, row := range tasksWithDeadlines {
+		task := row.Task
 		daysLeft := int(task.DueDate.Sub(now).Hours() / 24)
 
 		var daysLeftText string
@@ -451,8 +660,8 @@ func showDeadlines(app *App) error {
 			daysLeftText = green(fmt.Sprintf("%d days", daysLeft))
 		}
 
-		fmt.Printf("%-5d %-30s %-12s %-15s %s\n",
-			task.ID,
+		fmt.Printf("%-*s %-30s %-12s %-15s %s\n", width,
+			row.ID,
 			truncateString(task.Title, 28),
 			task.Priority.String(),
 			task.DueDate.Format("2006-01-02"),
@@ -0,0 +1,201 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"taskmaster/internal/mail"
+	"taskmaster/internal/report"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// digestPeriods maps a digest period to the days of completed work it covers
+var digestPeriods = map[string]int{
+	"daily":  1,
+	"weekly": 7,
+}
+
+// buildDigest prepares the digest email of the workspace
+func (a *App) buildDigest(period, from string, to []string, now time.Time) (*mail.Message, *report.Digest, error) {
+	days, ok := digestPeriods[period]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown period %q (use daily or weekly)", period)
+	}
+
+	tasks, err := a.GetAllTasks()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error retrieving tasks: %w", err)
+	}
+
+	workspace := filepath.Base(a.Root())
+	title := fmt.Sprintf("TaskMaster %s digest", period)
+	d := report.BuildDigest(title, workspace, tasks, now, days, a.config.Digest.DueSoonDays)
+
+	var text, html bytes.Buffer
+	if err := report.RenderDigest(&text, d, "text"); err != nil {
+		return nil, nil, err
+	}
+	if err := report.RenderDigest(&html, d, "html"); err != nil {
+		return nil, nil, err
+	}
+
+	msg := &mail.Message{
+		From: from,
+		To:   to,
+		Subject: fmt.Sprintf("[%s] %d overdue, %d due soon, %d completed", workspace,
+			len(d.Overdue), len(d.DueSoon), len(d.Completed)),
+		Date: now,
+		Text: text.String(),
+		HTML: html.String(),
+	}
+	return msg, d, nil
+}
+
+// digestCommand sends the email digest or runs a local stand-in mail server
+func digestCommand(app *App, args []string) error {
+	if len(args) < 1 {
+		return errors.New("digest subcommand is required: send or receive")
+	}
+
+	switch args[0] {
+	case "send":
+		return sendDigest(app, args[1:])
+	case "receive":
+		return receiveMail(args[1:])
+	default:
+		return fmt.Errorf("unknown digest subcommand: %s", args[0])
+	}
+}
+
+// sendDigest mails the digest to the configured recipients, or writes it to
+// an .eml file with --dry-run
+func sendDigest(app *App, args []string) error {
+	sendCmd := flag.NewFlagSet("digest send", flag.ExitOnError)
+	periodPtr := sendCmd.String("period", "weekly", "Period of completed work to cover: daily or weekly")
+	toPtr := sendCmd.String("to", "", "Comma-separated recipients, replacing the configured ones")
+	dryRunPtr := sendCmd.Bool("dry-run", false, "Write the message to a file instead of sending it")
+	outputPtr := sendCmd.String("output", "", "File written by --dry-run (defaults to digest-YYYY-MM-DD.eml)")
+
+	if err := sendCmd.Parse(args); err != nil {
+		return err
+	}
+
+	cfg := app.config.Digest
+	to := cfg.To
+	if *toPtr != "" {
+		to = parseTags(*toPtr)
+	}
+	if len(to) == 0 {
+		return errors.New("no recipients: set digest.to in .taskmaster/config.json or pass --to")
+	}
+
+	from := cfg.From
+	if from == "" {
+		if !*dryRunPtr {
+			return errors.New("no sender: set digest.from in .taskmaster/config.json")
+		}
+		from = "TaskMaster <taskmaster@localhost>"
+	}
+
+	now := time.Now()
+	msg, d, err := app.buildDigest(*periodPtr, from, to, now)
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	summary := fmt.Sprintf("%d overdue, %d due soon, %d completed", len(d.Overdue), len(d.DueSoon), len(d.Completed))
+
+	if *dryRunPtr {
+		data, err := msg.Bytes()
+		if err != nil {
+			return err
+		}
+		path := *outputPtr
+		if path == "" {
+			path = fmt.Sprintf("digest-%s.eml", now.Format("2006-01-02"))
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return fmt.Errorf("failed to write digest: %w", err)
+		}
+		fmt.Printf("Dry run: digest for %s written to %s (%s)\n", strings.Join(to, ", "), path, summary)
+		return nil
+	}
+
+	if cfg.SMTP.Host == "" {
+		return errors.New("no mail server: set digest.smtp.host in .taskmaster/config.json")
+	}
+	server := mail.Server{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.LoginPassword(),
+		TLS:      cfg.SMTP.TLS,
+	}
+	if err := mail.Send(server, msg); err != nil {
+		return fmt.Errorf("failed to send digest: %w", err)
+	}
+
+	fmt.Printf("%s Digest sent to %s (%s)\n", green("✓"), strings.Join(to, ", "), summary)
+	return nil
+}
+
+// receiveMail runs a local SMTP server that prints the messages it receives,
+// for trying out digests without a real mail server
+func receiveMail(args []string) error {
+	receiveCmd := flag.NewFlagSet("digest receive", flag.ExitOnError)
+	addrPtr := receiveCmd.String("addr", "localhost:2525", "Address to listen on")
+	dirPtr := receiveCmd.String("dir", "", "Also save each message as an .eml file in this directory")
+
+	if err := receiveCmd.Parse(args); err != nil {
+		return err
+	}
+
+	faint := color.New(color.Faint).SprintFunc()
+	var received atomic.Int64
+
+	receiver := &mail.Receiver{Handle: func(from string, to []string, data []byte) {
+		n := received.Add(1)
+		subject := ""
+		if msg, err := netmail.ReadMessage(bytes.NewReader(data)); err == nil {
+			subject = msg.Header.Get("Subject")
+		}
+		fmt.Printf("%s message from %s to %s: %s (%d bytes)\n", time.Now().Format("15:04:05"), from,
+			strings.Join(to, ", "), subject, len(data))
+
+		if *dirPtr != "" {
+			path := filepath.Join(*dirPtr, fmt.Sprintf("message-%s-%d.eml", time.Now().Format("20060102-150405"), n))
+			if err := os.WriteFile(path, data, 0644); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to save message: %v\n", err)
+				return
+			}
+			fmt.Println(faint("  saved to " + path))
+		}
+	}}
+
+	listener, err := net.Listen("tcp", *addrPtr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Receiving mail on %s (press Ctrl+C to stop)\n", *addrPtr)
+	fmt.Println(faint(`Send to it with "smtp": {"host": "localhost", "port": ` + portOf(*addrPtr) + `, "tls": "none"}`))
+	return receiver.Serve(listener)
+}
+
+// portOf returns the port of a listen address
+func portOf(addr string) string {
+	_, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return port
+}
@@ -0,0 +1,317 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"taskmaster/internal/formats"
+	"taskmaster/internal/models"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// exportTasks writes all tasks in another tool's format
+func exportTasks(app *App, args []string) error {
+	exportCmd := flag.NewFlagSet("export", flag.ExitOnError)
+	formatPtr := exportCmd.String("format", "", "Export format: todotxt, taskwarrior or ics")
+	outputPtr := exportCmd.String("output", "", "Output file (defaults to stdout)")
+	eventsPtr := exportCmd.Bool("events", false, "Write due dates as all-day events instead of to-dos (ics only)")
+
+	if err := exportCmd.Parse(args); err != nil {
+		return err
+	}
+
+	tasks, err := app.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("error retrieving tasks: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outputPtr != "" {
+		file, err := os.Create(*outputPtr)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch *formatPtr {
+	case "todotxt":
+		err = formats.EncodeTodoTxt(out, tasks)
+	case "taskwarrior":
+		err = formats.EncodeTaskwarrior(out, tasks)
+	case "ics":
+		err = formats.EncodeICal(out, tasks, formats.ICalOptions{Events: *eventsPtr})
+	case "":
+		return errors.New("export format is required (--format)")
+	default:
+		return fmt.Errorf("unsupported export format: %s", *formatPtr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to export tasks: %w", err)
+	}
+
+	if *outputPtr != "" {
+		fmt.Printf("Exported %d tasks to %s\n", len(tasks), *outputPtr)
+	}
+	return nil
+}
+
+// importTasks creates tasks from a file written by another tool
+func importTasks(app *App, args []string) error {
+	importCmd := flag.NewFlagSet("import", flag.ExitOnError)
+	formatPtr := importCmd.String("format", "", "Import format: todotxt, taskwarrior, ics or csv")
+	dryRunPtr := importCmd.Bool("dry-run", false, "Preview the tasks without importing them")
+	allOrNothingPtr := importCmd.Bool("all-or-nothing", false, "Import nothing if any record fails validation")
+	mapPtr := importCmd.String("map", "", "CSV column mapping, e.g. title=Summary,due=Deadline (csv only)")
+	priorityMapPtr := importCmd.String("priority-map", "", "CSV priority values, e.g. Blocker=3,Major=high (csv only)")
+	delimiterPtr := importCmd.String("delimiter", ",", "CSV field delimiter (csv only)")
+	var dateFormats []string
+	importCmd.Func("date-format", "CSV date format such as DD/MM/YYYY or a Go layout; repeatable (csv only)", func(s string) error {
+		dateFormats = append(dateFormats, s)
+		return nil
+	})
+
+	if err := parseInterspersed(importCmd, args); err != nil {
+		return err
+	}
+	if importCmd.NArg() < 1 {
+		return errors.New("file to import is required")
+	}
+	path := importCmd.Arg(0)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	var records []*models.Task
+	switch *formatPtr {
+	case "todotxt":
+		records, err = formats.DecodeTodoTxt(file)
+	case "taskwarrior":
+		records, err = formats.DecodeTaskwarrior(file)
+	case "ics":
+		records, err = formats.DecodeICal(file)
+	case "csv":
+		opts := formats.CSVOptions{DateFormats: dateFormats}
+		if opts.Columns, err = formats.ParseCSVMapping(*mapPtr); err != nil {
+			return err
+		}
+		if opts.Priorities, err = formats.ParseCSVPriorities(*priorityMapPtr); err != nil {
+			return err
+		}
+		if delimiter := []rune(*delimiterPtr); len(delimiter) == 1 {
+			opts.Comma = delimiter[0]
+		} else if *delimiterPtr == `\t` {
+			opts.Comma = '\t'
+		} else {
+			return fmt.Errorf("delimiter must be a single character, got %q", *delimiterPtr)
+		}
+		records, err = formats.DecodeCSV(file, opts)
+	case "":
+		return errors.New("import format is required (--format)")
+	default:
+		return fmt.Errorf("unsupported import format: %s", *formatPtr)
+	}
+
+	// Per-record problems are collected; anything else aborts the import
+	recordErrs, ok := lineErrors(err)
+	if !ok {
+		return fmt.Errorf("invalid %s file: %w", *formatPtr, err)
+	}
+
+	// Apply the same validation the import itself will use, before writing anything
+	var valid []*models.Task
+	for i, record := range records {
+		if err := validateImport(record); err != nil {
+			recordErrs = append(recordErrs, fmt.Errorf("record %d (%q): %w", i+1, record.Title, err))
+			continue
+		}
+		valid = append(valid, record)
+	}
+
+	if *dryRunPtr {
+		if err := printImportPreview(app, valid); err != nil {
+			return err
+		}
+		printImportErrors(recordErrs)
+		return nil
+	}
+
+	if len(recordErrs) > 0 && *allOrNothingPtr {
+		printImportErrors(recordErrs)
+		return fmt.Errorf("no tasks imported: %d records failed validation", len(recordErrs))
+	}
+
+	// With --all-or-nothing, remember what each row replaces so a failure
+	// part way through can be undone, and hold back post-hooks and webhook
+	// events until every row is saved
+	var createdTasks, previous []*models.Task
+	fail := func(err error) error {
+		if !*allOrNothingPtr {
+			return err
+		}
+		app.releaseNotices(false)
+		if rollbackErr := app.rollbackImport(createdTasks, previous); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("failed to undo the import: %w", rollbackErr))
+		}
+		return fmt.Errorf("%w; no tasks imported", err)
+	}
+	if *allOrNothingPtr {
+		app.holdNotices()
+	}
+
+	created, updated, skipped := 0, 0, 0
+	for _, record := range valid {
+		if *allOrNothingPtr {
+			existing, err := app.FindImported(record)
+			if err != nil {
+				return fail(err)
+			}
+			if existing != nil {
+				previous = append(previous, existing)
+			}
+		}
+
+		task, outcome, err := app.ImportTask(record)
+		if err != nil {
+			return fail(fmt.Errorf("failed to import task %q: %w", record.Title, err))
+		}
+
+		switch outcome {
+		case ImportCreated:
+			createdTasks = append(createdTasks, task)
+			created++
+			fmt.Printf("Imported task %d: %s\n", task.ID, task.Title)
+		case ImportUpdated:
+			updated++
+			fmt.Printf("Updated task %d: %s\n", task.ID, task.Title)
+		case ImportSkipped:
+			skipped++
+			fmt.Printf("Kept task %d: %s (changed here since it was exported)\n", task.ID, task.Title)
+		}
+	}
+
+	app.releaseNotices(true)
+	fmt.Printf("Imported %d tasks from %s (%d new, %d updated, %d kept)\n", len(valid), path, created, updated, skipped)
+
+	if len(recordErrs) > 0 {
+		printImportErrors(recordErrs)
+		return fmt.Errorf("%d records could not be imported", len(recordErrs))
+	}
+	return nil
+}
+
+// rollbackImport undoes an import that failed part way through, deleting
+// the tasks it created and restoring the ones it updated. Hooks do not run
+// and no events are sent, since the changes are being undone rather than made
+// and nothing was reported about them.
+func (a *App) rollbackImport(created, previous []*models.Task) error {
+	var errs []error
+	for _, task := range created {
+		start := time.Now()
+		err := a.storage.DeleteTask(task.ID)
+		a.observe(storageDelete, start)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("task %d: %w", task.ID, err))
+		}
+	}
+	for _, task := range previous {
+		start := time.Now()
+		err := a.storage.UpdateTask(task)
+		a.observe(storageUpdate, start)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("task %d: %w", task.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lineErrors splits a decoder error into its per-record errors. It reports
+// false if the error is not made up entirely of *formats.LineError values.
+func lineErrors(err error) ([]error, bool) {
+	if err == nil {
+		return nil, true
+	}
+
+	var errs []error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	} else {
+		errs = []error{err}
+	}
+
+	for _, e := range errs {
+		var lineErr *formats.LineError
+		if !errors.As(e, &lineErr) {
+			return nil, false
+		}
+	}
+	return errs, true
+}
+
+// printImportErrors lists the records that could not be imported
+func printImportErrors(errs []error) {
+	if len(errs) == 0 {
+		return
+	}
+
+	red := color.New(color.FgRed, color.Bold).SprintFunc()
+	fmt.Println()
+	fmt.Println(red(fmt.Sprintf("%d records failed validation:", len(errs))))
+	for _, err := range errs {
+		fmt.Printf("  %v\n", err)
+	}
+}
+
+// printImportPreview lists the tasks an import would create or update
+func printImportPreview(app *App, records []*models.Task) error {
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Println(cyan(fmt.Sprintf("DRY RUN — %d tasks would be imported", len(records))))
+	fmt.Printf("%-8s %-30s %-10s %-10s %-12s %s\n",
+		cyan("ACTION"), cyan("TITLE"), cyan("PRIORITY"), cyan("PROGRESS"), cyan("DUE DATE"), cyan("TAGS"))
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, record := range records {
+		action := green(fmt.Sprintf("%-8s", "create"))
+		existing, err := app.FindImported(record)
+		if err != nil {
+			return err
+		}
+		if existing != nil {
+			action = yellow(fmt.Sprintf("%-8s", fmt.Sprintf("update %d", existing.ID)))
+			if newerLocally(existing, record) {
+				action = fmt.Sprintf("%-8s", fmt.Sprintf("keep %d", existing.ID))
+			}
+		}
+
+		due := "-"
+		if !record.DueDate.IsZero() {
+			due = record.DueDate.Format("2006-01-02")
+		}
+		progress := fmt.Sprintf("%d%%", record.Progress)
+		if record.Completed {
+			progress = "done"
+		}
+
+		fmt.Printf("%s %-30s %-10s %-10s %-12s %s\n",
+			action,
+			truncateString(record.Title, 28),
+			record.Priority.String(),
+			progress,
+			due,
+			strings.Join(record.Tags, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"taskmaster/internal/models"
+	"testing"
+	"time"
+)
+
+// writeHook installs a hook script in the workspace of app and trusts the
+// workspace to run it
+func writeHook(t *testing.T, app *App, name, script string) {
+	t.Helper()
+
+	if _, err := setTrusted(app.Root(), true); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(app.hooksDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(app.hooksDir(), name), []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAllOrNothingImportReportsNothingWhenUndone(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts are shell scripts")
+	}
+	app := newTestApp(t)
+
+	// The second row is rejected after the first has been saved
+	writeHook(t, app, "pre-add", `grep -q '"title":"rejected"' && { echo no >&2; exit 1; }; exit 0`+"\n")
+	log := filepath.Join(t.TempDir(), "post-add.log")
+	writeHook(t, app, "post-add", "cat >> "+log+"\n")
+
+	var events []string
+	app.OnTaskEvent(func(event string, task *models.Task) {
+		events = append(events, event)
+	})
+
+	file := filepath.Join(t.TempDir(), "todo.txt")
+	if err := os.WriteFile(file, []byte("accepted\nrejected\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := importTasks(app, []string{"--format", "todotxt", "--all-or-nothing", file}); err == nil {
+		t.Fatal("import succeeded, want the rejected row to fail it")
+	}
+
+	if tasks, _ := app.GetAllTasks(); len(tasks) != 0 {
+		t.Errorf("%d tasks left after the import was undone", len(tasks))
+	}
+	if len(events) != 0 {
+		t.Errorf("events %v sent for an import that was undone", events)
+	}
+	if _, err := os.Stat(log); !os.IsNotExist(err) {
+		t.Error("post-add hook ran for an import that was undone")
+	}
+
+	// Once every row is saved, the held hooks and events run
+	if err := os.WriteFile(file, []byte("accepted\nalso accepted\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := importTasks(app, []string{"--format", "todotxt", "--all-or-nothing", file}); err != nil {
+		t.Fatalf("import error: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("got events %v, want two created events", events)
+	}
+	if _, err := os.Stat(log); err != nil {
+		t.Error("post-add hook did not run after a successful import")
+	}
+}
+
+func TestImportKeepsModifiedTimeAndNewerLocalChanges(t *testing.T) {
+	app := newTestApp(t)
+	modified := time.Date(2025, 12, 3, 9, 0, 0, 0, time.UTC)
+	record := func(title string, at time.Time) *models.Task {
+		return &models.Task{UUID: "0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b2a10", Title: title, Priority: models.Medium, UpdatedAt: at}
+	}
+
+	task, outcome, err := app.ImportTask(record("Imported", modified))
+	if err != nil || outcome != ImportCreated {
+		t.Fatalf("ImportTask() = %v, %v; want a new task", outcome, err)
+	}
+	if stored, _ := app.GetTask(task.ID); !stored.UpdatedAt.Equal(modified) {
+		t.Errorf("created task modified at %v, want %v", stored.UpdatedAt, modified)
+	}
+
+	// A later change made elsewhere is applied, keeping its time
+	later := modified.Add(time.Hour)
+	if _, outcome, err := app.ImportTask(record("Changed elsewhere", later)); err != nil || outcome != ImportUpdated {
+		t.Fatalf("ImportTask() = %v, %v; want an update", outcome, err)
+	}
+	if stored, _ := app.GetTask(task.ID); stored.Title != "Changed elsewhere" || !stored.UpdatedAt.Equal(later) {
+		t.Errorf("updated task is %q modified at %v", stored.Title, stored.UpdatedAt)
+	}
+
+	// Once edited here, an older record does not overwrite the edit
+	if err := app.UpdateTaskDetails(task.ID, "Edited here", "", time.Time{}, models.Medium); err != nil {
+		t.Fatal(err)
+	}
+	if _, outcome, err := app.ImportTask(record("Changed elsewhere", later)); err != nil || outcome != ImportSkipped {
+		t.Fatalf("ImportTask() = %v, %v; want it skipped", outcome, err)
+	}
+	if stored, _ := app.GetTask(task.ID); stored.Title != "Edited here" {
+		t.Errorf("title = %q, want the local edit kept", stored.Title)
+	}
+}
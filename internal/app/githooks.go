@@ -0,0 +1,174 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"taskmaster/internal/git"
+	"taskmaster/internal/storage"
+
+	"github.com/fatih/color"
+)
+
+// gitHook manages and runs the git hooks that update tasks from commit messages
+func gitHook(app *App, args []string) error {
+	if len(args) < 1 {
+		return errors.New("git-hook subcommand is required: install, uninstall or run")
+	}
+
+	switch args[0] {
+	case "install":
+		return installGitHooks(app, args[1:])
+	case "uninstall":
+		return uninstallGitHooks(app)
+	case "run":
+		if len(args) < 2 {
+			return errors.New("hook name is required")
+		}
+		switch args[1] {
+		case "commit-msg":
+			if len(args) < 3 {
+				return errors.New("commit message file is required")
+			}
+			return runCommitMsgHook(app, args[2])
+		case "post-commit":
+			return runPostCommitHook(app)
+		default:
+			return fmt.Errorf("unknown hook: %s", args[1])
+		}
+	default:
+		return fmt.Errorf("unknown git-hook subcommand: %s", args[0])
+	}
+}
+
+// installGitHooks writes the commit-msg and post-commit hooks
+func installGitHooks(app *App, args []string) error {
+	installCmd := flag.NewFlagSet("git-hook install", flag.ExitOnError)
+	forcePtr := installCmd.Bool("force", false, "Replace existing hooks not installed by taskmaster")
+
+	if err := installCmd.Parse(args); err != nil {
+		return err
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		binary = "taskmaster"
+	}
+
+	installed, err := git.InstallHooks(app.Root(), binary, *forcePtr)
+	if err != nil {
+		return fmt.Errorf("failed to install git hooks: %w", err)
+	}
+
+	for _, path := range installed {
+		fmt.Printf("Installed %s\n", path)
+	}
+	fmt.Println("Commit messages like 'fixes #12' or 'progress #12 60%' will now update tasks")
+	return nil
+}
+
+// uninstallGitHooks removes the hooks written by installGitHooks
+func uninstallGitHooks(app *App) error {
+	removed, err := git.UninstallHooks(app.Root())
+	if err != nil {
+		return fmt.Errorf("failed to uninstall git hooks: %w", err)
+	}
+
+	if len(removed) == 0 {
+		fmt.Println("No taskmaster git hooks installed")
+	}
+	for _, path := range removed {
+		fmt.Printf("Removed %s\n", path)
+	}
+	return nil
+}
+
+// runCommitMsgHook rejects commit messages that set an impossible progress.
+// References to unknown tasks only get a warning, since "fixes #45" usually
+// means an issue in the code host rather than a task.
+func runCommitMsgHook(app *App, messageFile string) error {
+	data, err := os.ReadFile(messageFile)
+	if err != nil {
+		return fmt.Errorf("failed to read commit message: %w", err)
+	}
+
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	for _, action := range git.ParseMessage(git.StripComments(string(data))) {
+		if action.Kind == git.ActionProgress && (action.Progress < 0 || action.Progress > 100) {
+			return fmt.Errorf("commit message sets progress of task #%d to %d%%: progress must be between 0 and 100",
+				action.TaskID, action.Progress)
+		}
+		if _, err := app.GetTask(action.TaskID); err != nil {
+			fmt.Fprintf(os.Stderr, "%s commit message mentions #%d, which is not a task (%v); it will be left alone\n",
+				yellow("taskmaster:"), action.TaskID, err)
+		}
+	}
+
+	return nil
+}
+
+// runPostCommitHook applies the task updates requested by the latest commit.
+// The commit has already been made, so problems are reported but not fatal.
+func runPostCommitHook(app *App) error {
+	hash, message, err := git.HeadMessage(app.Root())
+	if err != nil {
+		return fmt.Errorf("failed to read commit: %w", err)
+	}
+	short := hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	for _, action := range git.ParseMessage(message) {
+		var err error
+		var note string
+
+		switch action.Kind {
+		case git.ActionComplete:
+			err = app.CompleteTask(action.TaskID)
+			note = fmt.Sprintf("Completed by commit %s", short)
+		case git.ActionProgress:
+			err = app.UpdateTaskProgress(action.TaskID, action.Progress)
+			note = fmt.Sprintf("Progress set to %d%% by commit %s", action.Progress, short)
+		}
+		if err == nil {
+			err = app.AnnotateTask(action.TaskID, note)
+		}
+
+		if errors.Is(err, storage.ErrNotFound) {
+			continue // Not a task; the commit-msg hook already said so
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s task #%d: %v\n", yellow("taskmaster:"), action.TaskID, err)
+			continue
+		}
+		fmt.Printf("%s task #%d: %s\n", green("taskmaster:"), action.TaskID, note)
+	}
+
+	return nil
+}
+
+// printTaskCommits lists the commits whose message references a task
+func printTaskCommits(app *App, id int64) {
+	if !git.IsRepository(app.Root()) {
+		return
+	}
+
+	commits, err := git.CommitsReferencing(app.Root(), id)
+	if err != nil || len(commits) == 0 {
+		return
+	}
+
+	bold := color.New(color.Bold).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+
+	fmt.Printf("%s:\n", bold("Commits"))
+	for _, commit := range commits {
+		fmt.Printf("  %s %s %s (%s)\n", yellow(commit.Hash), commit.Date, commit.Subject, commit.Author)
+	}
+}
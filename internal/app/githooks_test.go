@@ -0,0 +1,37 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"taskmaster/internal/models"
+	"testing"
+	"time"
+)
+
+func TestCommitMsgHook(t *testing.T) {
+	app := newTestApp(t)
+	if _, err := app.CreateTask("Fix login", "", time.Time{}, models.Medium, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		message string
+		wantErr bool
+	}{
+		{"Fix the login form\n\nfixes #1", false},
+		{"Bump dependencies\n\nfixes #45", false}, // An issue number, not a task
+		{"progress #1 60%", false},
+		{"progress #1 150%", true},
+		{"progress #45 150%", true},
+	}
+	for _, tt := range tests {
+		file := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+		if err := os.WriteFile(file, []byte(tt.message), 0644); err != nil {
+			t.Fatal(err)
+		}
+		err := runCommitMsgHook(app, file)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("runCommitMsgHook(%q) error = %v, want error %v", tt.message, err, tt.wantErr)
+		}
+	}
+}
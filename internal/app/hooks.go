@@ -0,0 +1,224 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"taskmaster/internal/hooks"
+	"taskmaster/internal/models"
+	"taskmaster/internal/storage"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Actions that run lifecycle hooks, each with a pre- and a post- hook
+const (
+	hookAdd      = "add"
+	hookModify   = "modify"
+	hookComplete = "complete"
+	hookDelete   = "delete"
+)
+
+// hookActions lists the actions in the order they are shown
+var hookActions = []string{hookAdd, hookModify, hookComplete, hookDelete}
+
+// hooksDir returns the directory holding the workspace's hook scripts
+func (a *App) hooksDir() string {
+	return filepath.Join(a.Root(), storage.DirName, "hooks")
+}
+
+// hookRunner returns the runner for the workspace's hook scripts, or nil when
+// there are none, when this process was itself started by a hook, or when
+// the user has not trusted the workspace to run them
+func (a *App) hookRunner() *hooks.Runner {
+	if os.Getenv("TASKMASTER_HOOK") != "" {
+		return nil
+	}
+	if entries, err := os.ReadDir(a.hooksDir()); err != nil || len(entries) == 0 {
+		return nil
+	}
+	if !a.trusted() {
+		a.untrustedHooks.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: not running the hooks in %s because the workspace is not trusted\n", a.hooksDir())
+			fmt.Fprintln(os.Stderr, "Review them and run 'taskmaster workspace trust' to allow them.")
+		})
+		return nil
+	}
+	runner := hooks.NewRunner(a.hooksDir())
+	runner.Dir = a.Root()
+	runner.Env = []string{storage.EnvDir + "=" + a.Root()}
+	return runner
+}
+
+// preHook runs the pre- scripts for an action on a task as it is about to be
+// stored. A script exiting non-zero aborts the action; a script printing a
+// task replaces it, although its identity and timestamps are kept.
+func (a *App) preHook(action string, task *models.Task) error {
+	runner := a.hookRunner()
+	if runner == nil {
+		return nil
+	}
+	if action == hookAdd && task.UUID == "" {
+		task.UUID = models.NewUUID() // Let pre-add scripts see the UUID
+	}
+
+	input, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	output, err := runner.Filter("pre-"+action, input)
+	if err != nil {
+		var hookErr *hooks.Error
+		if errors.As(err, &hookErr) {
+			return invalid("pre-%s hook rejected the task: %v", action, hookErr)
+		}
+		return err
+	}
+	if output == nil || action == hookDelete {
+		return nil
+	}
+
+	var changed models.Task
+	if err := json.Unmarshal(output, &changed); err != nil {
+		return invalid("pre-%s hook printed an invalid task: %v", action, err)
+	}
+	changed.ID = task.ID
+	changed.UUID = task.UUID
+	changed.CreatedAt = task.CreatedAt
+	changed.UpdatedAt = task.UpdatedAt
+	changed.Tags = normalizeTags(changed.Tags)
+	if changed.Completed {
+		changed.MarkCompleted(time.Now())
+	} else {
+		changed.MarkOpen()
+	}
+	if err := validateImport(&changed); err != nil {
+		return invalid("pre-%s hook printed an invalid task: %v", action, err)
+	}
+
+	*task = changed
+	return nil
+}
+
+// postHook runs the post- scripts for an action once the task is stored.
+// The action has already happened, so failures are only reported.
+func (a *App) postHook(action string, task *models.Task) {
+	runner := a.hookRunner()
+	if runner == nil {
+		return
+	}
+
+	a.notice(func() {
+		input, err := json.Marshal(task)
+		if err != nil {
+			return
+		}
+		if err := runner.Notify("post-"+action, input); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: post-%s hook failed: %v\n", action, err)
+		}
+	})
+}
+
+// notice runs fn, which reports a change through a post-hook or a task
+// event, or holds it back while holdNotices is in effect
+func (a *App) notice(fn func()) {
+	if a.holding {
+		a.held = append(a.held, fn)
+		return
+	}
+	fn()
+}
+
+// holdNotices holds back post-hooks and task events until releaseNotices
+func (a *App) holdNotices() {
+	a.holding, a.held = true, nil
+}
+
+// releaseNotices stops holding back post-hooks and task events. The held
+// ones run when the changes were kept and are dropped when they were undone.
+func (a *App) releaseNotices(kept bool) {
+	held := a.held
+	a.holding, a.held = false, nil
+	if kept {
+		for _, fn := range held {
+			fn()
+		}
+	}
+}
+
+// updateTask stores a changed task, running the hooks of the action around it
+func (a *App) updateTask(action string, task *models.Task) error {
+	return a.updateTaskAt(action, task, time.Time{})
+}
+
+// updateTaskAt is updateTask for a change made at a given time, such as the
+// modification time of an imported record. The zero time means now, as does
+// a storage that always records its own modification times.
+func (a *App) updateTaskAt(action string, task *models.Task, modified time.Time) error {
+	if err := a.preHook(action, task); err != nil {
+		return err
+	}
+	start := time.Now()
+	var err error
+	if saver, ok := a.storage.(interface{ SaveTask(*models.Task) error }); ok && !modified.IsZero() {
+		task.UpdatedAt = modified
+		err = saver.SaveTask(task)
+	} else {
+		err = a.storage.UpdateTask(task)
+	}
+	a.observe(storageUpdate, start)
+	if err != nil {
+		return err
+	}
+	a.postHook(action, task)
+	return nil
+}
+
+// changeAction returns the action a change amounts to: completing the task
+// if it was open and no longer is, modifying it otherwise
+func changeAction(wasCompleted bool, task *models.Task) string {
+	if task.Completed && !wasCompleted {
+		return hookComplete
+	}
+	return hookModify
+}
+
+// listHooks shows the hook scripts installed in the workspace
+func listHooks(app *App, args []string) error {
+	if len(args) > 0 && args[0] != "list" {
+		return fmt.Errorf("unknown hooks subcommand: %s", args[0])
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	faint := color.New(color.Faint).SprintFunc()
+
+	runner := hooks.NewRunner(app.hooksDir())
+	found := false
+	for _, action := range hookActions {
+		for _, hook := range []string{"pre-" + action, "post-" + action} {
+			scripts, err := runner.Scripts(hook)
+			if err != nil {
+				return err
+			}
+			for _, script := range scripts {
+				if !found {
+					fmt.Println(cyan("HOOK") + "            " + cyan("SCRIPT"))
+					found = true
+				}
+				fmt.Printf("%-15s %s\n", hook, filepath.Base(script))
+			}
+		}
+	}
+
+	if !found {
+		fmt.Println("No hooks installed.")
+	} else if !app.trusted() {
+		yellow := color.New(color.FgYellow).SprintFunc()
+		fmt.Println(yellow("These hooks do not run until you trust the workspace with 'taskmaster workspace trust'"))
+	}
+	fmt.Println(faint("Hook scripts are read from " + app.hooksDir()))
+	return nil
+}
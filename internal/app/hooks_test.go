@@ -0,0 +1,60 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"taskmaster/internal/models"
+	"testing"
+	"time"
+)
+
+func TestHooksOnlyRunInTrustedWorkspaces(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hook scripts are shell scripts")
+	}
+	app := newTestApp(t)
+
+	marker := filepath.Join(t.TempDir(), "ran")
+	writeHook(t, app, "post-add", "touch "+marker+"\n")
+
+	// A workspace that was cloned rather than created is not trusted
+	if _, err := setTrusted(app.Root(), false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.CreateTask("untrusted", "", time.Time{}, models.Low, nil); err != nil {
+		t.Fatalf("CreateTask() error: %v", err)
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Fatal("hook ran in an untrusted workspace")
+	}
+
+	if _, err := setTrusted(app.Root(), true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.CreateTask("trusted", "", time.Time{}, models.Low, nil); err != nil {
+		t.Fatalf("CreateTask() error: %v", err)
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("hook did not run once the workspace was trusted")
+	}
+}
+
+func TestSetTrusted(t *testing.T) {
+	app := newTestApp(t)
+
+	if app.trusted() {
+		t.Fatal("new workspace is trusted before init")
+	}
+	for _, step := range []struct {
+		trust, changed bool
+	}{{true, true}, {true, false}, {false, true}, {false, false}} {
+		changed, err := setTrusted(app.Root(), step.trust)
+		if err != nil || changed != step.changed {
+			t.Errorf("setTrusted(%v) = %v, %v; want %v", step.trust, changed, err, step.changed)
+		}
+		if app.trusted() != step.trust {
+			t.Errorf("trusted() = %v after setTrusted(%v)", app.trusted(), step.trust)
+		}
+	}
+}
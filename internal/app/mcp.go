@@ -0,0 +1,319 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"taskmaster/internal/storage"
+	"time"
+)
+
+// mcpProtocolVersions lists the Model Context Protocol revisions the server
+// speaks, newest first
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// mcpResourceNotFound is the MCP error code for an unknown resource
+const mcpResourceNotFound = -32002
+
+// mcpTaskURIPrefix starts the URI of every task resource
+const mcpTaskURIPrefix = "taskmaster://task/"
+
+// mcpTool describes a tool and the RPC method that implements it
+type mcpTool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+	method      string
+}
+
+// mcpTools are the tools offered to agents. Each one is backed by the
+// matching rpc method, so arguments are validated exactly as they are there.
+var mcpTools = []mcpTool{
+	{
+		Name:        "list_tasks",
+		Description: "List tasks in the workspace, optionally filtered by status, tag, priority or text.",
+		InputSchema: objectSchema(nil, map[string]any{
+			"status":   map[string]any{"type": "string", "enum": []string{"open", "completed", "overdue"}},
+			"tag":      map[string]any{"type": "string"},
+			"priority": prioritySchema,
+			"search":   map[string]any{"type": "string", "description": "Text to look for in titles and descriptions"},
+		}),
+		method: "listTasks",
+	},
+	{
+		Name:        "get_task",
+		Description: "Get a task by its ID.",
+		InputSchema: objectSchema([]string{"id"}, map[string]any{"id": idSchema}),
+		method:      "getTask",
+	},
+	{
+		Name:        "create_task",
+		Description: "Create a task.",
+		InputSchema: objectSchema([]string{"title"}, map[string]any{
+			"title":       map[string]any{"type": "string"},
+			"description": map[string]any{"type": "string"},
+			"due_date":    map[string]any{"type": "string", "description": "YYYY-MM-DD or RFC 3339"},
+			"priority":    prioritySchema,
+			"tags":        map[string]any{"type": "array", "items": map[string]any{"type": "string"}},
+		}),
+		method: "createTask",
+	},
+	{
+		Name:        "update_progress",
+		Description: "Set the progress of a task from 0 to 100; 100 completes it and lower values reopen it.",
+		InputSchema: objectSchema([]string{"id", "progress"}, map[string]any{
+			"id":       idSchema,
+			"progress": map[string]any{"type": "integer", "minimum": 0, "maximum": 100},
+		}),
+		method: "updateProgress",
+	},
+	{
+		Name:        "complete_task",
+		Description: "Mark a task as completed.",
+		InputSchema: objectSchema([]string{"id"}, map[string]any{"id": idSchema}),
+		method:      "complete",
+	},
+}
+
+// idSchema is the JSON schema of a task ID argument
+var idSchema = map[string]any{"type": "integer", "description": "Task ID as shown by list_tasks"}
+
+// prioritySchema is the JSON schema of a priority argument
+var prioritySchema = map[string]any{
+	"type":        "integer",
+	"minimum":     0,
+	"maximum":     3,
+	"description": "0 = Low, 1 = Medium, 2 = High, 3 = Critical",
+}
+
+// objectSchema returns the JSON schema of an object with the given properties
+func objectSchema(required []string, properties map[string]any) map[string]any {
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// mcpServer answers Model Context Protocol requests for one client
+type mcpServer struct {
+	app *App
+
+	// subscribed holds the URIs of resources the client wants updates for
+	subscribed map[string]bool
+}
+
+// runMCP serves the Model Context Protocol on stdin and stdout
+func runMCP(app *App, args []string) error {
+	mcpCmd := flag.NewFlagSet("mcp", flag.ExitOnError)
+	pollPtr := mcpCmd.Duration("poll", time.Second, "How often to check for changes made by other processes")
+
+	if err := mcpCmd.Parse(args); err != nil {
+		return err
+	}
+
+	m := &mcpServer{app: app, subscribed: make(map[string]bool)}
+	return serveStdio(app, *pollPtr, m.handle, m.changed)
+}
+
+// handle dispatches an MCP method
+func (m *mcpServer) handle(method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(params, &p)
+
+		version := mcpProtocolVersions[0]
+		for _, supported := range mcpProtocolVersions {
+			if p.ProtocolVersion == supported {
+				version = supported
+			}
+		}
+
+		return map[string]any{
+			"protocolVersion": version,
+			"capabilities": map[string]any{
+				"tools":     map[string]any{"listChanged": false},
+				"resources": map[string]any{"subscribe": true, "listChanged": true},
+			},
+			"serverInfo": map[string]any{"name": "taskmaster", "version": "1.0.0"},
+			"instructions": fmt.Sprintf("Tasks of the TaskMaster workspace in %s. Use the tools to find and change tasks; "+
+				"each task is also a resource at %s{id}.", m.app.Root(), mcpTaskURIPrefix),
+		}, nil
+
+	case "notifications/initialized", "notifications/cancelled":
+		return nil, nil
+
+	case "ping":
+		return map[string]any{}, nil
+
+	case "tools/list":
+		return map[string]any{"tools": mcpTools}, nil
+
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{rpcInvalidParams, "invalid params: " + err.Error()}
+		}
+		return m.callTool(p.Name, p.Arguments)
+
+	case "resources/list":
+		return m.listResources()
+
+	case "resources/templates/list":
+		return map[string]any{"resourceTemplates": []map[string]any{{
+			"uriTemplate": mcpTaskURIPrefix + "{id}",
+			"name":        "task",
+			"description": "A task record, as stored in the workspace",
+			"mimeType":    "application/json",
+		}}}, nil
+
+	case "resources/read":
+		uri, err := resourceURI(params)
+		if err != nil {
+			return nil, err
+		}
+		return m.readResource(uri)
+
+	case "resources/subscribe", "resources/unsubscribe":
+		uri, err := resourceURI(params)
+		if err != nil {
+			return nil, err
+		}
+		if method == "resources/subscribe" {
+			m.subscribed[uri] = true
+		} else {
+			delete(m.subscribed, uri)
+		}
+		return map[string]any{}, nil
+
+	default:
+		return nil, &rpcError{rpcMethodNotFound, "method not found: " + method}
+	}
+}
+
+// callTool runs a tool. Failures such as a missing task or invalid input are
+// reported in the result so the agent can see and correct them.
+func (m *mcpServer) callTool(name string, arguments json.RawMessage) (any, error) {
+	for _, tool := range mcpTools {
+		if tool.Name != name {
+			continue
+		}
+
+		result, err := callTaskMethod(m.app, tool.method, arguments)
+		if err != nil {
+			return mcpToolResult(err.Error(), true), nil
+		}
+
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return mcpToolResult(string(data), false), nil
+	}
+
+	return nil, &rpcError{rpcInvalidParams, "unknown tool: " + name}
+}
+
+// mcpToolResult wraps text in a tool call result
+func mcpToolResult(text string, isError bool) map[string]any {
+	return map[string]any{
+		"content": []map[string]any{{"type": "text", "text": text}},
+		"isError": isError,
+	}
+}
+
+// listResources lists one resource per task
+func (m *mcpServer) listResources() (any, error) {
+	tasks, err := m.app.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []map[string]any{}
+	for _, task := range tasks {
+		resources = append(resources, map[string]any{
+			"uri":         taskURI(task.ID),
+			"name":        fmt.Sprintf("task %d", task.ID),
+			"title":       task.Title,
+			"description": fmt.Sprintf("%s priority, %d%% done, %s", task.Priority, task.Progress, strings.ToLower(statusName(task))),
+			"mimeType":    "application/json",
+		})
+	}
+	return map[string]any{"resources": resources}, nil
+}
+
+// readResource returns the JSON record of the task named by uri
+func (m *mcpServer) readResource(uri string) (any, error) {
+	id, err := strconv.ParseInt(strings.TrimPrefix(uri, mcpTaskURIPrefix), 10, 64)
+	if !strings.HasPrefix(uri, mcpTaskURIPrefix) || err != nil {
+		return nil, &rpcError{mcpResourceNotFound, "resource not found: " + uri}
+	}
+
+	task, err := m.app.GetTask(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, &rpcError{mcpResourceNotFound, "resource not found: " + uri}
+		}
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(task, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{"contents": []map[string]any{{
+		"uri":      uri,
+		"mimeType": "application/json",
+		"text":     string(data),
+	}}}, nil
+}
+
+// changed tells the client that the resource list changed when tasks were
+// created or deleted, and which subscribed resources were updated
+func (m *mcpServer) changed(s *rpcServer, changes []taskChange) {
+	listChanged := false
+	for _, change := range changes {
+		switch change.Action {
+		case changeCreated, changeDeleted:
+			listChanged = true
+		}
+
+		uri := taskURI(change.Task.ID)
+		if m.subscribed[uri] {
+			s.write(rpcNotification{JSONRPC: "2.0", Method: "notifications/resources/updated", Params: map[string]any{"uri": uri}})
+		}
+	}
+
+	if listChanged {
+		s.write(rpcNotification{JSONRPC: "2.0", Method: "notifications/resources/list_changed", Params: map[string]any{}})
+	}
+}
+
+// taskURI returns the resource URI of a task
+func taskURI(id int64) string {
+	return mcpTaskURIPrefix + strconv.FormatInt(id, 10)
+}
+
+// resourceURI extracts the uri parameter of a resource request
+func resourceURI(params json.RawMessage) (string, error) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil || p.URI == "" {
+		return "", &rpcError{rpcInvalidParams, "invalid params: uri is required"}
+	}
+	return p.URI, nil
+}
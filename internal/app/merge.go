@@ -0,0 +1,136 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"taskmaster/internal/git"
+	"taskmaster/internal/models"
+	"taskmaster/internal/storage"
+
+	"github.com/fatih/color"
+)
+
+// Renumbering records a task whose display ID was changed by Reconcile
+type Renumbering struct {
+	Task  *models.Task
+	OldID int64
+}
+
+// Reconcile gives tasks that share a display ID after a merge unique IDs. The
+// oldest task keeps the ID and the others move past the highest ID ever used,
+// so no task takes the ID of a deleted one.
+func (a *App) Reconcile() ([]Renumbering, error) {
+	tasks, err := a.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	next := storage.MaxID(tasks) + 1
+	if ids, ok := a.storage.(interface{ NextID() (int64, error) }); ok {
+		if next, err = ids.NextID(); err != nil {
+			return nil, err
+		}
+	}
+	seen := make(map[int64]bool)
+	var changes []Renumbering
+
+	for _, task := range tasks {
+		if !seen[task.ID] {
+			seen[task.ID] = true
+			continue
+		}
+		changes = append(changes, Renumbering{Task: task, OldID: task.ID})
+		task.ID = next
+		next++
+	}
+
+	for _, change := range changes {
+		if err := a.updateTask(hookModify, change.Task); err != nil {
+			return nil, fmt.Errorf("failed to renumber task #%d: %w", change.OldID, err)
+		}
+	}
+
+	return changes, nil
+}
+
+// reconcileTasks renumbers tasks whose display IDs collide
+func reconcileTasks(app *App, args []string) error {
+	reconcileCmd := flag.NewFlagSet("reconcile", flag.ExitOnError)
+
+	if err := reconcileCmd.Parse(args); err != nil {
+		return err
+	}
+
+	changes, err := app.Reconcile()
+	if err != nil {
+		return fmt.Errorf("failed to reconcile task IDs: %w", err)
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("Task IDs are already unique")
+		return nil
+	}
+
+	yellow := color.New(color.FgYellow).SprintFunc()
+	for _, change := range changes {
+		fmt.Printf("#%d -> #%s  %s\n", change.OldID, yellow(change.Task.ID), change.Task.Title)
+	}
+	fmt.Printf("Renumbered %d task(s)\n", len(changes))
+	return nil
+}
+
+// mergeDriver installs and runs the git merge driver for task files
+func mergeDriver(app *App, args []string) error {
+	if len(args) < 1 {
+		return errors.New("merge-driver subcommand is required: install or run")
+	}
+
+	switch args[0] {
+	case "install":
+		binary, err := os.Executable()
+		if err != nil {
+			binary = "taskmaster"
+		}
+
+		path, err := git.InstallMergeDriver(app.Root(), binary)
+		if err != nil {
+			return fmt.Errorf("failed to install merge driver: %w", err)
+		}
+
+		fmt.Printf("Registered the taskmaster merge driver in %s\n", path)
+		fmt.Println("Commit .gitattributes and run 'taskmaster merge-driver install' in each clone")
+		return nil
+	case "run":
+		if len(args) < 4 {
+			return errors.New("usage: merge-driver run BASE OURS THEIRS")
+		}
+		return runMergeDriver(args[1], args[2], args[3])
+	default:
+		return fmt.Errorf("unknown merge-driver subcommand: %s", args[0])
+	}
+}
+
+// runMergeDriver merges the three versions of a task file git hands over,
+// writing the result to the OURS file as git expects
+func runMergeDriver(basePath, oursPath, theirsPath string) error {
+	var versions [3][]byte
+	for i, path := range []string{basePath, oursPath, theirsPath} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		versions[i] = data
+	}
+
+	merged, err := storage.MergeTaskJSON(versions[0], versions[1], versions[2])
+	if err != nil {
+		return fmt.Errorf("failed to merge task file: %w", err)
+	}
+
+	if err := os.WriteFile(oursPath, merged, 0644); err != nil {
+		return fmt.Errorf("failed to write merged task file: %w", err)
+	}
+	return nil
+}
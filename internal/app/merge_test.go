@@ -0,0 +1,40 @@
+package app
+
+import (
+	"taskmaster/internal/models"
+	"testing"
+	"time"
+)
+
+func TestReconcileDoesNotReuseDeletedIDs(t *testing.T) {
+	app := newTestApp(t)
+	for _, title := range []string{"first", "second", "third"} {
+		if _, err := app.CreateTask(title, "", time.Time{}, models.Medium, nil); err != nil {
+			t.Fatalf("CreateTask(%q) error: %v", title, err)
+		}
+	}
+	if err := app.DeleteTask(3); err != nil {
+		t.Fatalf("DeleteTask() error: %v", err)
+	}
+
+	// A merge leaves the second task sharing the first one's ID
+	second, err := app.GetTask(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.ID = 1
+	if err := app.storage.UpdateTask(second); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := app.Reconcile()
+	if err != nil {
+		t.Fatalf("Reconcile() error: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("Reconcile() renumbered %d tasks, want 1", len(changes))
+	}
+	if got := changes[0].Task.ID; got != 4 {
+		t.Errorf("duplicate renumbered to #%d, want #4 (#2 is free but #3 was deleted)", got)
+	}
+}
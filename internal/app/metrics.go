@@ -0,0 +1,128 @@
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"taskmaster/internal/metrics"
+	"taskmaster/internal/models"
+	"time"
+)
+
+// Task operations counted by the metrics endpoint
+const (
+	operationCreate   = "create"
+	operationComplete = "complete"
+	operationDelete   = "delete"
+)
+
+// Storage calls timed by the metrics endpoint. Completing, reopening and
+// setting progress are all saved with an update.
+const (
+	storageCreate = "create_task"
+	storageGet    = "get_task"
+	storageGetAll = "get_all_tasks"
+	storageUpdate = "update_task"
+	storageDelete = "delete_task"
+)
+
+// appMetrics holds the counters and histograms collected while serving
+type appMetrics struct {
+	app        *App
+	operations metrics.Counter
+	storage    *metrics.Histogram
+}
+
+// enableMetrics starts counting task operations and timing storage calls
+func (a *App) enableMetrics() *appMetrics {
+	m := &appMetrics{app: a, storage: metrics.NewHistogram(metrics.DefaultBuckets)}
+	a.metrics = m
+
+	a.OnTaskEvent(func(event string, task *models.Task) {
+		switch event {
+		case EventCreated:
+			m.operations.Inc(operationCreate)
+		case EventCompleted:
+			m.operations.Inc(operationComplete)
+		case EventDeleted:
+			m.operations.Inc(operationDelete)
+		}
+	})
+	return m
+}
+
+// observe records how long a storage call that began at start took, when
+// metrics are enabled
+func (a *App) observe(call string, start time.Time) {
+	if a.metrics != nil {
+		a.metrics.storage.Observe(call, time.Since(start).Seconds())
+	}
+}
+
+// ServeHTTP writes the metrics in the Prometheus text format. Task gauges are
+// read from the workspace on each scrape, so they include changes made by
+// other processes; counters and latencies cover this server only.
+func (m *appMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tasks, err := m.app.GetAllTasks()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	var buf bytes.Buffer
+	writeTaskGauges(&buf, tasks)
+	m.operations.Write(&buf, "taskmaster_task_operations_total",
+		"Task operations performed through this server.", "operation",
+		operationCreate, operationComplete, operationDelete)
+	m.storage.Write(&buf, "taskmaster_storage_operation_duration_seconds",
+		"Time taken by task storage calls.", "operation")
+
+	w.Header().Set("Content-Type", metrics.ContentType)
+	w.Write(buf.Bytes())
+}
+
+// writeTaskGauges writes the gauges describing the tasks in the workspace
+func writeTaskGauges(buf *bytes.Buffer, tasks []*models.Task) {
+	statuses := []string{statusInProgress, statusOverdue, statusCompleted}
+	priorities := []models.Priority{models.Low, models.Medium, models.High, models.Critical}
+
+	counts := make(map[string]map[models.Priority]int)
+	for _, status := range statuses {
+		counts[status] = make(map[models.Priority]int)
+	}
+	open, progress := 0, 0
+	for _, task := range tasks {
+		counts[statusName(task)][task.Priority]++
+		if !task.Completed {
+			open++
+			progress += task.Progress
+		}
+	}
+
+	metrics.Header(buf, "taskmaster_tasks", "Tasks by status and priority.", "gauge")
+	for _, status := range statuses {
+		for _, priority := range priorities {
+			metrics.Sample(buf, "taskmaster_tasks", float64(counts[status][priority]),
+				"status", metricLabel(status), "priority", metricLabel(priority.String()))
+		}
+	}
+
+	overdue := 0
+	for _, n := range counts[statusOverdue] {
+		overdue += n
+	}
+	metrics.Header(buf, "taskmaster_tasks_overdue", "Open tasks past their due date.", "gauge")
+	metrics.Sample(buf, "taskmaster_tasks_overdue", float64(overdue))
+
+	average := 0.0
+	if open > 0 {
+		average = float64(progress) / float64(open)
+	}
+	metrics.Header(buf, "taskmaster_task_progress_average", "Average progress of open tasks, in percent.", "gauge")
+	metrics.Sample(buf, "taskmaster_task_progress_average", average)
+}
+
+// metricLabel turns a display name such as "In Progress" into a label value
+func metricLabel(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), " ", "_")
+}
@@ -0,0 +1,195 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"taskmaster/internal/config"
+	"taskmaster/internal/hooks"
+	"taskmaster/internal/storage"
+
+	"github.com/fatih/color"
+)
+
+// pluginPrefix starts the executable name of every plugin
+const pluginPrefix = "taskmaster-"
+
+// ExitError reports that a plugin exited with a non-zero status, which the
+// process should exit with as well
+type ExitError struct {
+	Code int
+}
+
+// Error implements the error interface
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
+// pluginInfo describes an executable providing a command
+type pluginInfo struct {
+	Name string
+	Path string
+}
+
+// pluginsDir returns the directory holding the workspace's own plugins
+func (a *App) pluginsDir() string {
+	return filepath.Join(a.Root(), storage.DirName, "plugins")
+}
+
+// findPlugin returns the executable of the plugin providing a command,
+// preferring the workspace's plugins to those on PATH. The workspace's own
+// plugins are only used once the user trusts the workspace.
+func (a *App) findPlugin(name string) (string, bool) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	if path, ok := a.workspacePlugin(name); ok && a.trusted() {
+		return path, true
+	}
+	if path, err := exec.LookPath(pluginPrefix + name); err == nil {
+		return path, true
+	}
+	return "", false
+}
+
+// workspacePlugin returns the executable of the workspace's own plugin
+// providing a command, whether or not the workspace is trusted
+func (a *App) workspacePlugin(name string) (string, bool) {
+	path, err := exec.LookPath(filepath.Join(a.pluginsDir(), pluginPrefix+name))
+	return path, err == nil
+}
+
+// plugins returns every plugin that can run, in lookup order; a plugin hidden
+// by an earlier one of the same name is left out
+func (a *App) plugins() []pluginInfo {
+	dirs := filepath.SplitList(os.Getenv("PATH"))
+	if a.trusted() {
+		dirs = append([]string{a.pluginsDir()}, dirs...)
+	}
+
+	var found []pluginInfo
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !strings.HasPrefix(entry.Name(), pluginPrefix) {
+				continue
+			}
+			info, err := os.Stat(filepath.Join(dir, entry.Name()))
+			if err != nil || !hooks.Executable(info) {
+				continue
+			}
+
+			name := strings.TrimPrefix(entry.Name(), pluginPrefix)
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			found = append(found, pluginInfo{Name: name, Path: filepath.Join(dir, entry.Name())})
+		}
+	}
+	return found
+}
+
+// pluginEnv returns the variables describing the workspace to a plugin
+func (a *App) pluginEnv() []string {
+	var env []string
+	if bin, err := os.Executable(); err == nil {
+		env = append(env, "TASKMASTER_BIN="+bin)
+	}
+
+	dataDir := filepath.Join(a.Root(), storage.DirName)
+	if info, err := os.Stat(dataDir); err == nil && info.IsDir() {
+		env = append(env,
+			storage.EnvDir+"="+a.Root(),
+			"TASKMASTER_DATA_DIR="+dataDir,
+			"TASKMASTER_CONFIG="+filepath.Join(dataDir, config.FileName),
+		)
+	}
+
+	if data, err := json.Marshal(a.config); err == nil {
+		env = append(env, "TASKMASTER_CONFIG_JSON="+string(data))
+	}
+	return env
+}
+
+// runPlugin runs the plugin providing a command, passing on the arguments
+// and the terminal
+func runPlugin(app *App, name string, args []string) error {
+	path, ok := app.findPlugin(name)
+	if !ok {
+		if plugin, ok := app.workspacePlugin(name); ok {
+			return fmt.Errorf("unknown command: %s\n%s is not run because the workspace is not trusted; review it and run 'taskmaster workspace trust' to allow it", name, plugin)
+		}
+		return fmt.Errorf("unknown command: %s\nRun 'taskmaster help' for usage", name)
+	}
+
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(), app.pluginEnv()...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 {
+			return &ExitError{Code: exitErr.ExitCode()}
+		}
+		return fmt.Errorf("failed to run plugin %s: %w", name, err)
+	}
+	return nil
+}
+
+// pluginCommand reports whether a name that is not a built-in command is
+// provided by a plugin on PATH, which may run outside a workspace
+func pluginCommand(name string) bool {
+	if strings.ContainsAny(name, `/\`) {
+		return false
+	}
+	_, err := exec.LookPath(pluginPrefix + name)
+	return err == nil
+}
+
+// listPlugins shows the available plugins
+func listPlugins(app *App, args []string) error {
+	if len(args) > 0 && args[0] != "list" {
+		return fmt.Errorf("unknown plugins subcommand: %s", args[0])
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	faint := color.New(color.Faint).SprintFunc()
+
+	if entries, err := os.ReadDir(app.pluginsDir()); err == nil && len(entries) > 0 && !app.trusted() {
+		fmt.Println(yellow("Plugins in " + app.pluginsDir() + " do not run until you trust the workspace with 'taskmaster workspace trust'"))
+	}
+
+	found := app.plugins()
+	if len(found) == 0 {
+		fmt.Println("No plugins found.")
+		fmt.Println(faint("Plugins are taskmaster-<command> executables in " + app.pluginsDir() + " or on your PATH"))
+		return nil
+	}
+
+	builtins := commandTable(nil)
+	fmt.Printf("%s %s\n", cyan(fmt.Sprintf("%-16s", "COMMAND")), cyan("PATH"))
+	for _, p := range found {
+		note := ""
+		if _, ok := builtins[p.Name]; ok {
+			note = " " + yellow("(hidden by the built-in command)")
+		}
+		fmt.Printf("%-16s %s%s\n", p.Name, p.Path, note)
+	}
+	return nil
+}
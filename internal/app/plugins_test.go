@@ -0,0 +1,44 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWorkspacePluginsNeedTrust(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	app := newTestApp(t)
+	t.Setenv("PATH", t.TempDir())
+
+	if err := os.MkdirAll(app.pluginsDir(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	plugin := filepath.Join(app.pluginsDir(), pluginPrefix+"lst")
+	if err := os.WriteFile(plugin, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if path, ok := app.findPlugin("lst"); ok {
+		t.Errorf("findPlugin() = %s in an untrusted workspace", path)
+	}
+	if found := app.plugins(); len(found) != 0 {
+		t.Errorf("plugins() = %v in an untrusted workspace", found)
+	}
+	if err := runPlugin(app, "lst", nil); err == nil {
+		t.Error("runPlugin() ran a plugin of an untrusted workspace")
+	}
+
+	if _, err := setTrusted(app.Root(), true); err != nil {
+		t.Fatal(err)
+	}
+	if path, ok := app.findPlugin("lst"); !ok || path != plugin {
+		t.Errorf("findPlugin() = %q, %v; want %s", path, ok, plugin)
+	}
+	if found := app.plugins(); len(found) != 1 || found[0].Name != "lst" {
+		t.Errorf("plugins() = %v, want the workspace plugin", found)
+	}
+}
@@ -0,0 +1,448 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"taskmaster/internal/models"
+	"taskmaster/internal/remind"
+	"taskmaster/internal/storage"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// reminder is a reminder about a task, either from the lead time of its
+// priority or requested with 'remind'
+type reminder struct {
+	Key  string
+	At   time.Time
+	Task *models.Task
+	// Scheduled is set for reminders requested with 'remind'
+	Scheduled bool
+}
+
+// remindersDir returns the directory holding the reminder state
+func (a *App) remindersDir() string {
+	return filepath.Join(a.Root(), storage.DirName, "reminders")
+}
+
+// leadTime returns how long before its due date a task of the given priority
+// is reminded of; false means reminders are off for the priority
+func (a *App) leadTime(p models.Priority) (time.Duration, bool, error) {
+	value := a.config.Reminders.LeadTime(p.String())
+	if value == "" || strings.EqualFold(value, "off") {
+		return 0, false, nil
+	}
+	lead, err := remind.ParseDuration(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid reminder lead time for %s priority: %w", p, err)
+	}
+	return lead, true, nil
+}
+
+// scheduledKey identifies a reminder requested with 'remind'
+func scheduledKey(s remind.Scheduled) string {
+	return "at:" + s.UUID + ":" + s.At.UTC().Format(time.RFC3339)
+}
+
+// reminders returns the reminders of open tasks that have not been sent yet,
+// earliest first. Each due date gets one reminder, so moving it, or changing
+// the lead time, brings the reminder back.
+func (a *App) reminders(state *remind.State, tasks []*models.Task) ([]reminder, error) {
+	var list []reminder
+	byUUID := make(map[string]*models.Task)
+	for _, task := range tasks {
+		byUUID[task.UUID] = task
+		if task.Completed || task.DueDate.IsZero() {
+			continue
+		}
+		lead, ok, err := a.leadTime(task.Priority)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		key := fmt.Sprintf("due:%s:%s:%s", task.UUID, task.DueDate.UTC().Format(time.RFC3339), lead)
+		if _, sent := state.Sent[key]; !sent {
+			list = append(list, reminder{Key: key, At: task.DueDate.Add(-lead), Task: task})
+		}
+	}
+
+	for _, s := range state.Scheduled {
+		if task, ok := byUUID[s.UUID]; ok && !task.Completed {
+			list = append(list, reminder{Key: scheduledKey(s), At: s.At, Task: task, Scheduled: true})
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].At.Before(list[j].At) })
+	return list, nil
+}
+
+// sendReminders delivers the reminders that are due and records them, so a
+// restarted daemon does not send them again. A reminder no notifier could
+// deliver is tried again later. Reminders of tasks that were completed or
+// deleted are forgotten.
+func (a *App) sendReminders(notifiers []remind.Notifier, now time.Time) ([]reminder, error) {
+	state, err := remind.Load(a.remindersDir())
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := a.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+	list, err := a.reminders(state, tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	var sent []reminder
+	for _, r := range list {
+		if r.At.After(now) {
+			break
+		}
+		if deliverReminder(notifiers, r, a.Root(), now) {
+			sent = append(sent, r)
+		}
+	}
+
+	// Notifiers can take a while, so the changes are applied to the state as
+	// it is now, keeping reminders requested or cancelled in the meantime
+	latest, err := remind.Load(a.remindersDir())
+	if err != nil {
+		return sent, err
+	}
+	open := make(map[string]bool)
+	for _, task := range tasks {
+		open[task.UUID] = !task.Completed
+	}
+
+	changed := false
+	dropped := make(map[string]bool)
+	for _, r := range sent {
+		if r.Scheduled {
+			dropped[r.Key] = true
+		} else {
+			latest.Sent[r.Key] = now
+			changed = true
+		}
+	}
+	for key := range state.Sent {
+		if parts := strings.SplitN(key, ":", 3); len(parts) < 3 || !open[parts[1]] {
+			if _, ok := latest.Sent[key]; ok {
+				delete(latest.Sent, key)
+				changed = true
+			}
+		}
+	}
+	for _, s := range state.Scheduled {
+		if !open[s.UUID] {
+			dropped[scheduledKey(s)] = true
+		}
+	}
+	kept := latest.Scheduled[:0]
+	for _, s := range latest.Scheduled {
+		if !dropped[scheduledKey(s)] {
+			kept = append(kept, s)
+		}
+	}
+	changed = changed || len(kept) != len(latest.Scheduled)
+	latest.Scheduled = kept
+
+	if changed {
+		if err := latest.Save(); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// deliverReminder passes a reminder to every notifier, reporting whether at
+// least one of them delivered it
+func deliverReminder(notifiers []remind.Notifier, r reminder, root string, now time.Time) bool {
+	delivered := false
+	for _, n := range notifiers {
+		if err := n.Notify(reminderMessage(r, root, now)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to deliver reminder for task %d: %v\n", r.Task.ID, err)
+			continue
+		}
+		delivered = true
+	}
+	return delivered
+}
+
+// reminderMessage describes a reminder for notifications
+func reminderMessage(r reminder, root string, now time.Time) remind.Message {
+	task := r.Task
+
+	var title string
+	switch {
+	case r.Scheduled:
+		title = fmt.Sprintf("Reminder: task %d", task.ID)
+	case now.After(task.DueDate):
+		title = fmt.Sprintf("Task %d is overdue", task.ID)
+	default:
+		title = fmt.Sprintf("Task %d is due in %s", task.ID, approxDuration(task.DueDate.Sub(now)))
+	}
+
+	body := fmt.Sprintf("%s (%s priority", task.Title, task.Priority)
+	if !task.DueDate.IsZero() {
+		body += ", due " + task.DueDate.Format("2006-01-02")
+	}
+	body += ")"
+
+	input, _ := json.Marshal(task)
+	return remind.Message{
+		Title:  title,
+		Body:   body,
+		Urgent: task.Priority >= models.High || statusName(task) == statusOverdue,
+		Env: []string{
+			storage.EnvDir + "=" + root,
+			"TASKMASTER_TASK_ID=" + strconv.FormatInt(task.ID, 10),
+			"TASKMASTER_TASK_TITLE=" + task.Title,
+			"TASKMASTER_TASK_PRIORITY=" + task.Priority.String(),
+			"TASKMASTER_TASK_DUE=" + task.FormatDueDate(),
+		},
+		Input: input,
+	}
+}
+
+// approxDuration formats a duration in the largest sensible unit
+func approxDuration(d time.Duration) string {
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%d min", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%d days", int(d.Hours()/24))
+	}
+}
+
+// reminderNotifiers returns the delivery methods configured for reminders.
+// Desktop notifications are skipped with a warning where they cannot be shown.
+func (a *App) reminderNotifiers() ([]remind.Notifier, error) {
+	var notifiers []remind.Notifier
+	for _, method := range a.config.Reminders.Notify {
+		switch strings.ToLower(method) {
+		case "desktop":
+			desktop := remind.Desktop{}
+			if err := desktop.Available(); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: desktop notifications are disabled: %v\n", err)
+				continue
+			}
+			notifiers = append(notifiers, desktop)
+		case "bell":
+			notifiers = append(notifiers, remind.Bell{Out: os.Stdout})
+		case "command":
+			if strings.TrimSpace(a.config.Reminders.Command) == "" {
+				return nil, errors.New(`reminders.notify includes "command" but reminders.command is not set`)
+			}
+			notifiers = append(notifiers, remind.Command{Line: a.config.Reminders.Command, Dir: a.Root()})
+		default:
+			return nil, fmt.Errorf("unknown reminder delivery method %q (use desktop, bell or command)", method)
+		}
+	}
+	return notifiers, nil
+}
+
+// runDaemon sends reminders as they fall due until interrupted
+func runDaemon(app *App, args []string) error {
+	daemonCmd := flag.NewFlagSet("daemon", flag.ExitOnError)
+	intervalPtr := daemonCmd.Duration("interval", 30*time.Second, "How often to check for reminders that are due")
+	oncePtr := daemonCmd.Bool("once", false, "Send the reminders that are due and exit, for running from cron")
+
+	if err := daemonCmd.Parse(args); err != nil {
+		return err
+	}
+	if *intervalPtr <= 0 {
+		return errors.New("interval must be positive")
+	}
+
+	notifiers, err := app.reminderNotifiers()
+	if err != nil {
+		return err
+	}
+	if len(notifiers) == 0 {
+		return errors.New("no reminder delivery method is available; set reminders.notify in .taskmaster/config.json to bell or command")
+	}
+
+	yellow := color.New(color.FgYellow, color.Bold).SprintFunc()
+	faint := color.New(color.Faint).SprintFunc()
+
+	check := func() error {
+		sent, err := app.sendReminders(notifiers, time.Now())
+		for _, r := range sent {
+			msg := reminderMessage(r, app.Root(), time.Now())
+			fmt.Printf("%s %s: %s\n", faint(time.Now().Format("15:04:05")), yellow(msg.Title), msg.Body)
+		}
+		return err
+	}
+
+	if *oncePtr {
+		return check()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Check again as soon as tasks change, so new and edited due dates count
+	changed := make(chan struct{}, 1)
+	go app.watchTasks(ctx, 2*time.Second, func() {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	go app.runWebhooks(ctx, 30*time.Second)
+
+	fmt.Printf("Sending reminders for %s (press Ctrl+C to stop)\n", app.Root())
+
+	ticker := time.NewTicker(*intervalPtr)
+	defer ticker.Stop()
+
+	for {
+		if err := check(); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-changed:
+		}
+	}
+}
+
+// remindCommand schedules, lists and cancels reminders
+func remindCommand(app *App, args []string) error {
+	if len(args) < 1 {
+		return errors.New(`usage: taskmaster remind ID WHEN (e.g. "in 2h"), remind list or remind cancel ID`)
+	}
+
+	switch args[0] {
+	case "list":
+		return listReminders(app)
+	case "cancel":
+		if len(args) != 2 {
+			return errors.New("usage: taskmaster remind cancel ID")
+		}
+		return cancelReminders(app, args[1])
+	}
+
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid task ID: %w", err)
+	}
+	if len(args) < 2 {
+		return errors.New(`when to remind is required, e.g. "in 2h", "tomorrow 9:00" or "2025-03-01 14:00"`)
+	}
+
+	task, err := app.GetTask(id)
+	if err != nil {
+		return err
+	}
+	if task.Completed {
+		return fmt.Errorf("task %d is already completed", id)
+	}
+
+	now := time.Now()
+	at, err := remind.ParseWhen(strings.Join(args[1:], " "), now)
+	if err != nil {
+		return err
+	}
+	if !at.After(now) {
+		return fmt.Errorf("%s is in the past", at.Format("2006-01-02 15:04"))
+	}
+
+	state, err := remind.Load(app.remindersDir())
+	if err != nil {
+		return err
+	}
+	state.Schedule(task.UUID, at)
+	if err := state.Save(); err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Reminder for task %d set for %s (in %s)\n", green("✓"), id, at.Format("Mon 2006-01-02 15:04"), approxDuration(at.Sub(now)))
+	fmt.Println("Reminders are sent by 'taskmaster daemon'")
+	return nil
+}
+
+// listReminders shows the reminders that have not been sent yet
+func listReminders(app *App) error {
+	state, err := remind.Load(app.remindersDir())
+	if err != nil {
+		return err
+	}
+	tasks, err := app.GetAllTasks()
+	if err != nil {
+		return err
+	}
+	list, err := app.reminders(state, tasks)
+	if err != nil {
+		return err
+	}
+
+	if len(list) == 0 {
+		fmt.Println("No upcoming reminders.")
+		return nil
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	faint := color.New(color.Faint).SprintFunc()
+
+	fmt.Printf("%-18s %-5s %-30s %s\n", cyan("WHEN"), cyan("ID"), cyan("TITLE"), cyan("REASON"))
+	fmt.Println(strings.Repeat("-", 70))
+	for _, r := range list {
+		reason := "due " + r.Task.FormatDueDate()
+		if r.Scheduled {
+			reason = "requested"
+		}
+		when := r.At.Local().Format("2006-01-02 15:04")
+		if r.At.Before(time.Now()) {
+			when = "now"
+		}
+		fmt.Printf("%-18s %-5d %-30s %s\n", when, r.Task.ID, truncateString(r.Task.Title, 28), faint(reason))
+	}
+	return nil
+}
+
+// cancelReminders removes the reminders requested for a task
+func cancelReminders(app *App, arg string) error {
+	id, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid task ID: %w", err)
+	}
+	task, err := app.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	state, err := remind.Load(app.remindersDir())
+	if err != nil {
+		return err
+	}
+	removed := state.Cancel(task.UUID)
+	if removed == 0 {
+		fmt.Printf("Task %d has no requested reminders\n", id)
+		return nil
+	}
+	if err := state.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("Cancelled %d reminder(s) for task %d\n", removed, id)
+	return nil
+}
@@ -0,0 +1,92 @@
+package app
+
+import (
+	"errors"
+	"taskmaster/internal/models"
+	"taskmaster/internal/remind"
+	"testing"
+	"time"
+)
+
+// notifierFunc adapts a function to remind.Notifier
+type notifierFunc func(remind.Message) error
+
+// Notify implements remind.Notifier
+func (f notifierFunc) Notify(m remind.Message) error {
+	return f(m)
+}
+
+// newDueTask creates a task due in an hour, so its reminder is due now
+func newDueTask(t *testing.T, app *App) *models.Task {
+	t.Helper()
+
+	task, err := app.CreateTask("Ship it", "", time.Now().Add(time.Hour), models.Medium, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return task
+}
+
+func TestUndeliveredRemindersAreKept(t *testing.T) {
+	app := newTestApp(t)
+	newDueTask(t, app)
+
+	failing := notifierFunc(func(remind.Message) error { return errors.New("notify-send not found") })
+	sent, err := app.sendReminders([]remind.Notifier{failing}, time.Now())
+	if err != nil {
+		t.Fatalf("sendReminders() error: %v", err)
+	}
+	if len(sent) != 0 {
+		t.Errorf("sendReminders() reported %d reminders sent, want none", len(sent))
+	}
+
+	var got []remind.Message
+	working := notifierFunc(func(m remind.Message) error {
+		got = append(got, m)
+		return nil
+	})
+	if _, err := app.sendReminders([]remind.Notifier{failing, working}, time.Now()); err != nil {
+		t.Fatalf("sendReminders() error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("reminder delivered %d times, want once", len(got))
+	}
+
+	// Once delivered, it is not sent again
+	if _, err := app.sendReminders([]remind.Notifier{working}, time.Now()); err != nil {
+		t.Fatalf("sendReminders() error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Errorf("reminder delivered %d times, want once", len(got))
+	}
+}
+
+func TestRemindersRequestedWhileSendingAreKept(t *testing.T) {
+	app := newTestApp(t)
+	task := newDueTask(t, app)
+	later := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+
+	// 'remind' runs while the notifier is busy
+	slow := notifierFunc(func(remind.Message) error {
+		state, err := remind.Load(app.remindersDir())
+		if err != nil {
+			return err
+		}
+		state.Schedule(task.UUID, later)
+		return state.Save()
+	})
+	if _, err := app.sendReminders([]remind.Notifier{slow}, time.Now()); err != nil {
+		t.Fatalf("sendReminders() error: %v", err)
+	}
+
+	state, err := remind.Load(app.remindersDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Scheduled) != 1 || !state.Scheduled[0].At.Equal(later) {
+		t.Errorf("scheduled reminders = %v, want the one requested while sending", state.Scheduled)
+	}
+	if len(state.Sent) != 1 {
+		t.Errorf("%d reminders recorded as sent, want 1", len(state.Sent))
+	}
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"taskmaster/internal/report"
+	"time"
+)
+
+// generateReport writes a status report of open, overdue and recently completed tasks
+func generateReport(app *App, args []string) error {
+	reportCmd := flag.NewFlagSet("report", flag.ExitOnError)
+	formatPtr := reportCmd.String("format", "markdown", "Report format: markdown or html")
+	outputPtr := reportCmd.String("output", "", "Output file (defaults to stdout)")
+	templatePtr := reportCmd.String("template", "", "Custom template file (Go template syntax)")
+	titlePtr := reportCmd.String("title", "Task Status Report", "Report title")
+	daysPtr := reportCmd.Int("days", 7, "Include tasks completed in the last N days")
+	printTemplatePtr := reportCmd.Bool("print-template", false, "Print the built-in template for the format and exit")
+
+	if err := reportCmd.Parse(args); err != nil {
+		return err
+	}
+	if *daysPtr < 0 {
+		return fmt.Errorf("days must not be negative")
+	}
+
+	if *printTemplatePtr {
+		source, err := report.DefaultTemplate(*formatPtr)
+		if err != nil {
+			return err
+		}
+		fmt.Print(source)
+		return nil
+	}
+
+	var source string
+	if *templatePtr != "" {
+		data, err := os.ReadFile(*templatePtr)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		source = string(data)
+	}
+
+	tasks, err := app.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("error retrieving tasks: %w", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outputPtr != "" {
+		file, err := os.Create(*outputPtr)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	r := report.Build(*titlePtr, tasks, time.Now(), *daysPtr)
+	if err := report.Render(out, r, *formatPtr, source); err != nil {
+		return fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	if *outputPtr != "" {
+		fmt.Printf("Report written to %s\n", *outputPtr)
+	}
+	return nil
+}
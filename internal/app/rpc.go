@@ -0,0 +1,470 @@
+package app
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"taskmaster/internal/models"
+	"taskmaster/internal/storage"
+	"time"
+)
+
+// JSON-RPC 2.0 error codes; the -3200x codes are TaskMaster's own
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+	rpcTaskNotFound   = -32001
+	rpcAmbiguousID    = -32002
+)
+
+// rpcRequest is a JSON-RPC request or notification
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// rpcResponse is a JSON-RPC response
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcNotification is a message sent to the client without a request
+type rpcNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+// rpcError is the error object of a failed call
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error implements the error interface
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// rpcIDParams names a task
+type rpcIDParams struct {
+	ID int64 `json:"id"`
+}
+
+// rpcCreateParams are the parameters of createTask
+type rpcCreateParams struct {
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	DueDate     string           `json:"due_date"`
+	Priority    *models.Priority `json:"priority"`
+	Tags        []string         `json:"tags"`
+}
+
+// rpcListParams are the filters of listTasks
+type rpcListParams struct {
+	Status   string `json:"status"`
+	Tag      string `json:"tag"`
+	Priority *int   `json:"priority"`
+	Search   string `json:"search"`
+}
+
+// rpcUpdateParams are the parameters of updateDetails; fields left out keep
+// their current value
+type rpcUpdateParams struct {
+	ID          int64            `json:"id"`
+	Title       *string          `json:"title"`
+	Description *string          `json:"description"`
+	DueDate     *string          `json:"due_date"`
+	Priority    *models.Priority `json:"priority"`
+	Tags        *[]string        `json:"tags"`
+}
+
+// rpcProgressParams are the parameters of updateProgress
+type rpcProgressParams struct {
+	ID       int64 `json:"id"`
+	Progress *int  `json:"progress"`
+}
+
+// rpcServer serves JSON-RPC requests for one client over a pair of streams.
+// The methods it offers are supplied by handle, and changed is told about
+// task changes so it can notify the client.
+type rpcServer struct {
+	tracker *changeTracker
+	handle  func(method string, params json.RawMessage) (any, error)
+	changed func(s *rpcServer, changes []taskChange)
+
+	// mu serializes calls and change detection; outMu serializes writes
+	mu    sync.Mutex
+	outMu sync.Mutex
+	out   *bufio.Writer
+
+	// framed is set when the client uses Content-Length headers, as LSP
+	// clients do, instead of one message per line. It is set by the reader
+	// and read by writers, including the one reporting changes.
+	framed atomic.Bool
+}
+
+// runRPC serves JSON-RPC 2.0 on stdin and stdout until stdin is closed
+func runRPC(app *App, args []string) error {
+	rpcCmd := flag.NewFlagSet("rpc", flag.ExitOnError)
+	pollPtr := rpcCmd.Duration("poll", time.Second, "How often to check for changes made by other processes")
+
+	if err := rpcCmd.Parse(args); err != nil {
+		return err
+	}
+
+	handle := func(method string, params json.RawMessage) (any, error) {
+		return callTaskMethod(app, method, params)
+	}
+	changed := func(s *rpcServer, changes []taskChange) {
+		for _, change := range changes {
+			s.write(rpcNotification{JSONRPC: "2.0", Method: "taskChanged", Params: change})
+		}
+	}
+
+	return serveStdio(app, *pollPtr, handle, changed)
+}
+
+// serveStdio runs an rpcServer on stdin and stdout, checking for changes made
+// by other processes every poll interval
+func serveStdio(app *App, poll time.Duration, handle func(string, json.RawMessage) (any, error),
+	changed func(*rpcServer, []taskChange)) error {
+	tracker, err := newChangeTracker(app)
+	if err != nil {
+		return err
+	}
+
+	s := &rpcServer{tracker: tracker, handle: handle, changed: changed, out: bufio.NewWriter(os.Stdout)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.watch(ctx, poll)
+	go app.runWebhooks(ctx, 30*time.Second)
+
+	return s.serve(os.Stdin)
+}
+
+// watch reports changes made outside this process, such as CLI edits
+func (s *rpcServer) watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.notifyChanges()
+			s.mu.Unlock()
+		}
+	}
+}
+
+// serve reads requests until EOF
+func (s *rpcServer) serve(in io.Reader) error {
+	reader := bufio.NewReader(in)
+	for {
+		message, err := s.readMessage(reader)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read request: %w", err)
+		}
+		if len(bytes.TrimSpace(message)) == 0 {
+			continue
+		}
+
+		s.handleMessage(message)
+	}
+}
+
+// readMessage reads one message, either a line of JSON or a block framed by
+// a Content-Length header
+func (s *rpcServer) readMessage(reader *bufio.Reader) ([]byte, error) {
+	line, err := reader.ReadBytes('\n')
+	if err != nil && (err != io.EOF || len(line) == 0) {
+		return nil, err
+	}
+
+	header := strings.TrimSpace(string(line))
+	if !strings.HasPrefix(strings.ToLower(header), "content-length:") {
+		return line, nil
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(header[len("content-length:"):]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %q", header)
+	}
+	s.framed.Store(true)
+
+	// Skip any further headers up to the blank line
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// handleMessage answers a single request or a batch
+func (s *rpcServer) handleMessage(message []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trimmed := bytes.TrimSpace(message)
+	if len(trimmed) > 0 && trimmed[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(trimmed, &batch); err != nil {
+			s.write(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{rpcParseError, "parse error: " + err.Error()}})
+			return
+		}
+		if len(batch) == 0 {
+			s.write(rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{rpcInvalidRequest, "empty batch"}})
+			return
+		}
+
+		var responses []rpcResponse
+		for _, item := range batch {
+			if response, ok := s.handleRequest(item); ok {
+				responses = append(responses, response)
+			}
+		}
+		if len(responses) > 0 {
+			s.write(responses)
+		}
+	} else if response, ok := s.handleRequest(trimmed); ok {
+		s.write(response)
+	}
+
+	// Tell the client about the changes its calls made
+	s.notifyChanges()
+}
+
+// handleRequest runs one request, returning false for notifications, which
+// get no response
+func (s *rpcServer) handleRequest(data []byte) (rpcResponse, bool) {
+	var req rpcRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{rpcParseError, "parse error: " + err.Error()}}, true
+	}
+
+	id := req.ID
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		return rpcResponse{JSONRPC: "2.0", ID: id, Error: &rpcError{rpcInvalidRequest, "invalid request"}}, true
+	}
+
+	result, err := s.handle(req.Method, req.Params)
+	if req.ID == nil {
+		return rpcResponse{}, false
+	}
+
+	response := rpcResponse{JSONRPC: "2.0", ID: id, Result: result}
+	if err != nil {
+		response.Result = nil
+		response.Error = toRPCError(err)
+	} else if result == nil {
+		response.Result = true
+	}
+	return response, true
+}
+
+// callTaskMethod dispatches a taskmaster RPC method to the App
+func callTaskMethod(app *App, method string, params json.RawMessage) (any, error) {
+	switch method {
+	case "createTask":
+		var p rpcCreateParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		dueDate, err := parseAPIDate(p.DueDate)
+		if err != nil {
+			return nil, err
+		}
+		priority := models.Medium
+		if p.Priority != nil {
+			priority = *p.Priority
+		}
+		task, err := app.CreateTask(p.Title, p.Description, dueDate, priority, p.Tags)
+		if err != nil {
+			return nil, err
+		}
+		return app.GetTask(task.ID)
+
+	case "getTask":
+		var p rpcIDParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return app.GetTask(p.ID)
+
+	case "listTasks":
+		var p rpcListParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		tasks, err := app.GetAllTasks()
+		if err != nil {
+			return nil, err
+		}
+		filter := taskFilter{Status: p.Status, Tag: p.Tag, Search: p.Search}
+		if p.Priority != nil {
+			filter.Priority = strconv.Itoa(*p.Priority)
+		}
+		return filter.apply(tasks), nil
+
+	case "updateDetails":
+		var p rpcUpdateParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		task, err := app.GetTask(p.ID)
+		if err != nil {
+			return nil, err
+		}
+		title, desc, dueDate, priority := task.Title, task.Description, task.DueDate, task.Priority
+		if p.Title != nil {
+			title = *p.Title
+		}
+		if p.Description != nil {
+			desc = *p.Description
+		}
+		if p.DueDate != nil {
+			if dueDate, err = parseAPIDate(*p.DueDate); err != nil {
+				return nil, err
+			}
+		}
+		if p.Priority != nil {
+			priority = *p.Priority
+		}
+		if err := app.EditTask(p.ID, title, desc, dueDate, priority, p.Tags); err != nil {
+			return nil, err
+		}
+		return app.GetTask(p.ID)
+
+	case "updateProgress":
+		var p rpcProgressParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if p.Progress == nil {
+			return nil, invalid("progress is required")
+		}
+		if err := app.UpdateTaskProgress(p.ID, *p.Progress); err != nil {
+			return nil, err
+		}
+		return app.GetTask(p.ID)
+
+	case "complete":
+		var p rpcIDParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		if err := app.CompleteTask(p.ID); err != nil {
+			return nil, err
+		}
+		return app.GetTask(p.ID)
+
+	case "delete":
+		var p rpcIDParams
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		return nil, app.DeleteTask(p.ID)
+
+	default:
+		return nil, &rpcError{rpcMethodNotFound, "method not found: " + method}
+	}
+}
+
+// notifyChanges passes the tasks that changed since the last check on to the
+// changed callback. The caller must hold s.mu.
+func (s *rpcServer) notifyChanges() {
+	changes, err := s.tracker.changes()
+	if err != nil || len(changes) == 0 {
+		return
+	}
+	s.changed(s, changes)
+}
+
+// write sends a message to the client using the framing the client uses
+func (s *rpcServer) write(message any) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return
+	}
+
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+
+	if s.framed.Load() {
+		fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n", len(data))
+		s.out.Write(data)
+	} else {
+		s.out.Write(data)
+		s.out.WriteByte('\n')
+	}
+	s.out.Flush()
+}
+
+// decodeParams parses named parameters, rejecting unknown ones
+func decodeParams(params json.RawMessage, v any) error {
+	if len(params) == 0 {
+		params = json.RawMessage("{}")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(params))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return &rpcError{rpcInvalidParams, "invalid params: " + err.Error()}
+	}
+	return nil
+}
+
+// toRPCError maps application and storage errors to JSON-RPC errors
+func toRPCError(err error) *rpcError {
+	var rpcErr *rpcError
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &rpcErr):
+		return rpcErr
+	case errors.As(err, &validationErr):
+		return &rpcError{rpcInvalidParams, err.Error()}
+	case errors.Is(err, storage.ErrNotFound):
+		return &rpcError{rpcTaskNotFound, err.Error()}
+	case errors.Is(err, storage.ErrAmbiguousID):
+		return &rpcError{rpcAmbiguousID, err.Error()}
+	default:
+		return &rpcError{rpcInternalError, err.Error()}
+	}
+}
@@ -0,0 +1,199 @@
+package app
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"taskmaster/internal/models"
+	"taskmaster/internal/scan"
+
+	"github.com/fatih/color"
+)
+
+// scanPriorities maps comment tags to the priority of the tasks created for them
+var scanPriorities = map[string]models.Priority{
+	"FIXME": models.High,
+	"TODO":  models.Medium,
+	"HACK":  models.Low,
+}
+
+// ScanResult describes the changes made when syncing tasks with source comments
+type ScanResult struct {
+	Created   []*models.Task
+	Updated   []*models.Task
+	Reopened  []*models.Task
+	Completed []*models.Task
+	Unchanged int
+}
+
+// commentKey identifies a comment independently of its line number, so tasks
+// follow comments as surrounding code moves
+func commentKey(file, kind, text string) string {
+	return file + "\x00" + kind + "\x00" + text
+}
+
+// SyncComments creates tasks for new comments, updates the location of known
+// ones, and completes tasks whose comment has disappeared from the scanned
+// paths. Paths are relative to the workspace root; an empty list means the
+// whole workspace. With dryRun set nothing is written.
+func (a *App) SyncComments(comments []scan.Comment, scanned []string, dryRun bool) (*ScanResult, error) {
+	tasks, err := a.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]*models.Task)
+	for _, task := range tasks {
+		if task.Source != nil {
+			existing[commentKey(task.Source.File, task.Source.Kind, task.Source.Text)] = task
+		}
+	}
+
+	result := &ScanResult{}
+	seen := make(map[string]bool)
+
+	for _, comment := range comments {
+		key := commentKey(comment.File, comment.Kind, comment.Text)
+		if seen[key] {
+			continue // Identical comments in one file share a task
+		}
+		seen[key] = true
+
+		task, ok := existing[key]
+		if !ok {
+			task, err = a.createCommentTask(comment, dryRun)
+			if err != nil {
+				return nil, err
+			}
+			result.Created = append(result.Created, task)
+			continue
+		}
+
+		reopen := task.Completed
+		if !reopen && task.Source.Line == comment.Line {
+			result.Unchanged++
+			continue
+		}
+
+		task.Source.Line = comment.Line
+		if reopen {
+			task.MarkOpen()
+			task.Progress = 0
+			result.Reopened = append(result.Reopened, task)
+		} else {
+			result.Updated = append(result.Updated, task)
+		}
+		if !dryRun {
+			if err := a.updateTask(hookModify, task); err != nil {
+				return nil, fmt.Errorf("failed to update task %d: %w", task.ID, err)
+			}
+		}
+	}
+
+	// Comments that were removed from the scanned files complete their task
+	for key, task := range existing {
+		if seen[key] || task.Completed || !withinPaths(task.Source.File, scanned) {
+			continue
+		}
+		if !dryRun {
+			if err := a.CompleteTask(task.ID); err != nil {
+				return nil, fmt.Errorf("failed to complete task %d: %w", task.ID, err)
+			}
+		}
+		result.Completed = append(result.Completed, task)
+	}
+
+	return result, nil
+}
+
+// createCommentTask creates the task linked to a newly found comment
+func (a *App) createCommentTask(comment scan.Comment, dryRun bool) (*models.Task, error) {
+	title := comment.Text
+	if title == "" {
+		title = fmt.Sprintf("%s in %s", comment.Kind, comment.File)
+	}
+
+	source := &models.SourceRef{File: comment.File, Line: comment.Line, Kind: comment.Kind, Text: comment.Text}
+	if dryRun {
+		return &models.Task{Title: title, Priority: scanPriorities[comment.Kind], Source: source}, nil
+	}
+
+	// The source is set up front so add hooks see the task as stored
+	task := &models.Task{
+		Title:    title,
+		Priority: scanPriorities[comment.Kind],
+		Tags:     []string{strings.ToLower(comment.Kind)},
+		Source:   source,
+	}
+	if err := a.createTask(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// withinPaths reports whether a file lies inside one of the scanned paths
+func withinPaths(file string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		if p == "" || file == p || strings.HasPrefix(file, strings.TrimSuffix(p, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// scanComments syncs tasks with TODO, FIXME and HACK comments in the workspace
+func scanComments(app *App, args []string) error {
+	scanCmd := flag.NewFlagSet("scan", flag.ExitOnError)
+	dryRunPtr := scanCmd.Bool("dry-run", false, "Show what would change without writing anything")
+
+	if err := parseInterspersed(scanCmd, args); err != nil {
+		return err
+	}
+
+	root := app.Root()
+	paths := scanCmd.Args()
+
+	comments, err := scan.Scan(root, paths)
+	if err != nil {
+		return fmt.Errorf("failed to scan source files: %w", err)
+	}
+
+	var scanned []string
+	for _, p := range paths {
+		scanned = append(scanned, scan.RelPath(root, p))
+	}
+
+	result, err := app.SyncComments(comments, scanned, *dryRunPtr)
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	yellow := color.New(color.FgYellow).SprintFunc()
+	blue := color.New(color.FgBlue).SprintFunc()
+	faint := color.New(color.Faint).SprintFunc()
+
+	if *dryRunPtr {
+		fmt.Println(faint("Dry run: no tasks were changed"))
+	}
+
+	for _, task := range result.Created {
+		fmt.Printf("%s %-30s %s\n", green("created  "), truncateString(task.Title, 28), faint(task.Source.Location()))
+	}
+	for _, task := range result.Updated {
+		fmt.Printf("%s %-30s %s\n", blue(fmt.Sprintf("moved %-4d", task.ID)), truncateString(task.Title, 28), faint(task.Source.Location()))
+	}
+	for _, task := range result.Reopened {
+		fmt.Printf("%s %-30s %s\n", yellow(fmt.Sprintf("reopen %-3d", task.ID)), truncateString(task.Title, 28), faint(task.Source.Location()))
+	}
+	for _, task := range result.Completed {
+		fmt.Printf("%s %-30s %s\n", green(fmt.Sprintf("done %-5d", task.ID)), truncateString(task.Title, 28), faint(task.Source.Location()+" (removed)"))
+	}
+
+	fmt.Printf("Scanned %d comments: %d new, %d moved, %d reopened, %d completed, %d unchanged\n",
+		len(comments), len(result.Created), len(result.Updated), len(result.Reopened), len(result.Completed), result.Unchanged)
+	return nil
+}
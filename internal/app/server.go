@@ -0,0 +1,636 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"taskmaster/internal/models"
+	"taskmaster/internal/storage"
+	"time"
+)
+
+//go:embed api/openapi.json
+var openAPISpec []byte
+
+//go:embed web
+var webFiles embed.FS
+
+// errPreconditionFailed is returned when an If-Match header does not match
+// the current version of a task
+var errPreconditionFailed = errors.New("task has been modified since it was read")
+
+// errForbidden is returned for requests from other sites or through host
+// names the server does not answer to, which protects the API from web pages
+// open in the same browser, including through DNS rebinding
+var errForbidden = errors.New("forbidden")
+
+// errUnsupportedMediaType is returned for request bodies that are not JSON,
+// which other sites could otherwise send without a preflight check
+var errUnsupportedMediaType = errors.New("request body must be application/json")
+
+// apiServer exposes the App over a JSON REST API and serves the web UI
+type apiServer struct {
+	app *App
+	mux *http.ServeMux
+
+	// hosts are the host names accepted besides loopback ones
+	hosts map[string]bool
+
+	// mu serializes writes so the ETag check and the update happen together
+	mu sync.Mutex
+
+	// subscribers receive a signal whenever the workspace's tasks change
+	subMu       sync.Mutex
+	subscribers map[chan struct{}]bool
+}
+
+// taskInput is the request body for creating and updating tasks; fields left
+// out of an update keep their current value
+type taskInput struct {
+	Title       *string          `json:"title"`
+	Description *string          `json:"description"`
+	DueDate     *string          `json:"due_date"`
+	Priority    *models.Priority `json:"priority"`
+	Tags        *[]string        `json:"tags"`
+}
+
+// progressInput is the request body for updating progress
+type progressInput struct {
+	Progress *int `json:"progress"`
+}
+
+// newAPIServer returns the HTTP handler serving the REST API and web UI
+func newAPIServer(app *App) *apiServer {
+	s := &apiServer{app: app, hosts: make(map[string]bool), subscribers: make(map[chan struct{}]bool)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/tasks", s.listTasks)
+	mux.HandleFunc("POST /api/tasks", s.createTask)
+	mux.HandleFunc("GET /api/tasks/{id}", s.getTask)
+	mux.HandleFunc("PATCH /api/tasks/{id}", s.updateTask)
+	mux.HandleFunc("DELETE /api/tasks/{id}", s.deleteTask)
+	mux.HandleFunc("PUT /api/tasks/{id}/progress", s.updateProgress)
+	mux.HandleFunc("POST /api/tasks/{id}/complete", s.completeTask)
+	mux.HandleFunc("GET /api/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPISpec)
+	})
+	mux.HandleFunc("GET /api/events", s.events)
+
+	// The web UI is served from the root
+	web, _ := fs.Sub(webFiles, "web")
+	mux.Handle("GET /", http.FileServerFS(web))
+
+	s.mux = mux
+	return s
+}
+
+// ServeHTTP implements http.Handler
+func (s *apiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := s.checkOrigin(r); err != nil {
+		writeError(w, err)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// allowHost accepts requests addressed to a host name other than a loopback one
+func (s *apiServer) allowHost(host string) {
+	s.hosts[strings.ToLower(host)] = true
+}
+
+// checkOrigin rejects requests addressed to an unknown host name and
+// requests sent by pages from another origin
+func (s *apiServer) checkOrigin(r *http.Request) error {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(r.Host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	if !isLoopbackHost(host) && !s.hosts[host] {
+		return fmt.Errorf("%w: unknown host %q (start serve with --allow-host %s to accept it)", errForbidden, host, host)
+	}
+
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !strings.EqualFold(u.Host, r.Host) {
+			return fmt.Errorf("%w: cross-origin request from %s", errForbidden, origin)
+		}
+	}
+	return nil
+}
+
+// isLoopbackHost reports whether a host name always refers to this machine
+func isLoopbackHost(host string) bool {
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// notify tells every connected event stream that tasks have changed
+func (s *apiServer) notify() {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default: // A change is already pending for this subscriber
+		}
+	}
+}
+
+// events streams a server-sent "changed" event whenever tasks change, whether
+// through the API, the CLI or an edited file
+func (s *apiServer) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan struct{}, 1)
+	s.subMu.Lock()
+	s.subscribers[ch] = true
+	s.subMu.Unlock()
+	defer func() {
+		s.subMu.Lock()
+		delete(s.subscribers, ch)
+		s.subMu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ch:
+			fmt.Fprint(w, "event: changed\ndata: {}\n\n")
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// serve runs the HTTP server until interrupted
+func serve(app *App, args []string) error {
+	serveCmd := flag.NewFlagSet("serve", flag.ExitOnError)
+	addrPtr := serveCmd.String("addr", "localhost:8080", "Address to listen on")
+	allowHostPtr := serveCmd.String("allow-host", "", "Comma-separated host names the server may also be reached by, such as a LAN name")
+
+	if err := serveCmd.Parse(args); err != nil {
+		return err
+	}
+
+	api := newAPIServer(app)
+	if host, _, err := net.SplitHostPort(*addrPtr); err == nil && host != "" {
+		api.allowHost(host)
+	}
+	for _, host := range strings.Split(*allowHostPtr, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			api.allowHost(host)
+		}
+	}
+	api.mux.Handle("GET /metrics", app.enableMetrics())
+	srv := &http.Server{
+		Addr:              *addrPtr,
+		Handler:           api,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Requests, including open event streams, end when the server is stopped
+	srv.BaseContext = func(net.Listener) context.Context { return ctx }
+
+	// Push changes to connected browsers, including edits made outside the server
+	go app.watchTasks(ctx, time.Second, api.notify)
+	go app.runWebhooks(ctx, 30*time.Second)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	fmt.Printf("Serving %s on http://%s (press Ctrl+C to stop)\n", app.Root(), displayAddr(*addrPtr))
+	fmt.Printf("Web interface: http://%s/\n", displayAddr(*addrPtr))
+	fmt.Printf("API description: http://%s/api/openapi.json\n", displayAddr(*addrPtr))
+	fmt.Printf("Prometheus metrics: http://%s/metrics\n", displayAddr(*addrPtr))
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("server stopped: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return srv.Shutdown(shutdownCtx)
+}
+
+// displayAddr turns a listen address such as ":8080" into one a browser can open
+func displayAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
+	}
+	return addr
+}
+
+// listTasks returns all tasks, optionally filtered by status, tag, priority
+// and a search term
+func (s *apiServer) listTasks(w http.ResponseWriter, r *http.Request) {
+	tasks, err := s.app.GetAllTasks()
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	filter := taskFilter{
+		Status:   query.Get("status"),
+		Tag:      query.Get("tag"),
+		Priority: query.Get("priority"),
+		Search:   query.Get("q"),
+	}
+
+	writeJSON(w, http.StatusOK, filter.apply(tasks))
+}
+
+// taskFilter selects tasks for the API and RPC list methods; empty fields
+// match every task
+type taskFilter struct {
+	// Status is open, completed or overdue
+	Status string
+	Tag    string
+	// Priority is a priority number (0-3) or name
+	Priority string
+	// Search matches the title or description, case-insensitively
+	Search string
+}
+
+// apply returns the tasks matching the filter, never nil
+func (f taskFilter) apply(tasks []*models.Task) []*models.Task {
+	result := []*models.Task{}
+	for _, task := range tasks {
+		if f.matches(task) {
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
+// matches reports whether a task passes the filter
+func (f taskFilter) matches(task *models.Task) bool {
+	if !matchesStatus(task, f.Status) || (f.Tag != "" && !task.HasTag(f.Tag)) {
+		return false
+	}
+	if f.Priority != "" && strconv.Itoa(int(task.Priority)) != f.Priority &&
+		!strings.EqualFold(task.Priority.String(), f.Priority) {
+		return false
+	}
+	if f.Search != "" {
+		search := strings.ToLower(f.Search)
+		if !strings.Contains(strings.ToLower(task.Title), search) &&
+			!strings.Contains(strings.ToLower(task.Description), search) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesStatus reports whether a task matches a status filter: open,
+// completed or overdue; an empty filter matches every task
+func matchesStatus(task *models.Task, status string) bool {
+	switch strings.ToLower(status) {
+	case "":
+		return true
+	case "open":
+		return !task.Completed
+	case "completed":
+		return task.Completed
+	case "overdue":
+		return statusName(task) == statusOverdue
+	default:
+		return false
+	}
+}
+
+// createTask creates a task from the request body
+func (s *apiServer) createTask(w http.ResponseWriter, r *http.Request) {
+	var input taskInput
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+	if input.Title == nil {
+		writeError(w, invalid("title is required"))
+		return
+	}
+
+	var desc string
+	if input.Description != nil {
+		desc = *input.Description
+	}
+	var dueDate time.Time
+	if input.DueDate != nil {
+		var err error
+		if dueDate, err = parseAPIDate(*input.DueDate); err != nil {
+			writeError(w, err)
+			return
+		}
+	}
+	priority := models.Medium
+	if input.Priority != nil {
+		priority = *input.Priority
+	}
+
+	var tags []string
+	if input.Tags != nil {
+		tags = *input.Tags
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, err := s.app.CreateTask(*input.Title, desc, dueDate, priority, tags)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/api/tasks/%d", task.ID))
+	s.writeTask(w, http.StatusCreated, task.ID)
+}
+
+// getTask returns a single task with its ETag
+func (s *apiServer) getTask(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	task, err := s.app.GetTask(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	etag := taskETag(task)
+	if match := r.Header.Get("If-None-Match"); match != "" && etagMatches(match, etag) {
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("ETag", etag)
+	writeJSON(w, http.StatusOK, task)
+}
+
+// updateTask changes the fields present in the request body
+func (s *apiServer) updateTask(w http.ResponseWriter, r *http.Request) {
+	var input taskInput
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.modify(w, r, func(task *models.Task) error {
+		title, desc, dueDate, priority := task.Title, task.Description, task.DueDate, task.Priority
+		if input.Title != nil {
+			title = *input.Title
+		}
+		if input.Description != nil {
+			desc = *input.Description
+		}
+		if input.DueDate != nil {
+			var err error
+			if dueDate, err = parseAPIDate(*input.DueDate); err != nil {
+				return err
+			}
+		}
+		if input.Priority != nil {
+			priority = *input.Priority
+		}
+
+		return s.app.EditTask(task.ID, title, desc, dueDate, priority, input.Tags)
+	})
+}
+
+// updateProgress sets the progress of a task
+func (s *apiServer) updateProgress(w http.ResponseWriter, r *http.Request) {
+	var input progressInput
+	if err := decodeBody(r, &input); err != nil {
+		writeError(w, err)
+		return
+	}
+	if input.Progress == nil {
+		writeError(w, invalid("progress is required"))
+		return
+	}
+
+	s.modify(w, r, func(task *models.Task) error {
+		return s.app.UpdateTaskProgress(task.ID, *input.Progress)
+	})
+}
+
+// completeTask marks a task as completed
+func (s *apiServer) completeTask(w http.ResponseWriter, r *http.Request) {
+	s.modify(w, r, func(task *models.Task) error {
+		return s.app.CompleteTask(task.ID)
+	})
+}
+
+// deleteTask deletes a task
+func (s *apiServer) deleteTask(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, err := s.app.GetTask(id)
+	if err == nil {
+		err = checkIfMatch(r, task)
+	}
+	if err == nil {
+		err = s.app.DeleteTask(id)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// modify applies change to the task named in the path after checking the
+// If-Match header, then responds with the updated task
+func (s *apiServer) modify(w http.ResponseWriter, r *http.Request, change func(*models.Task) error) {
+	id, err := pathID(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, err := s.app.GetTask(id)
+	if err == nil {
+		err = checkIfMatch(r, task)
+	}
+	if err == nil {
+		err = change(task)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	s.writeTask(w, http.StatusOK, id)
+}
+
+// writeTask responds with the current version of a task and its ETag
+func (s *apiServer) writeTask(w http.ResponseWriter, status int, id int64) {
+	task, err := s.app.GetTask(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("ETag", taskETag(task))
+	writeJSON(w, status, task)
+}
+
+// taskETag returns a strong entity tag for the stored state of a task
+func taskETag(task *models.Task) string {
+	data, _ := json.Marshal(task)
+	sum := sha256.Sum256(data)
+	return fmt.Sprintf(`"%x"`, sum[:12])
+}
+
+// checkIfMatch rejects a write whose If-Match header names an older version
+// of the task. Requests without the header are applied unconditionally.
+func checkIfMatch(r *http.Request, task *models.Task) error {
+	match := r.Header.Get("If-Match")
+	if match == "" || etagMatches(match, taskETag(task)) {
+		return nil
+	}
+	return errPreconditionFailed
+}
+
+// etagMatches reports whether a comma-separated If-Match or If-None-Match
+// header value includes etag
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// pathID parses the task ID from the request path
+func pathID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return 0, invalid("invalid task ID: %s", r.PathValue("id"))
+	}
+	return id, nil
+}
+
+// parseAPIDate accepts YYYY-MM-DD or RFC 3339 dates; an empty string clears the date
+func parseAPIDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, invalid("invalid due_date %q: use YYYY-MM-DD or RFC 3339", value)
+	}
+	return t, nil
+}
+
+// decodeBody parses a JSON request body, rejecting unknown fields
+func decodeBody(r *http.Request, v any) error {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return errUnsupportedMediaType
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<20))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return invalid("invalid request body: %v", err)
+	}
+	return nil
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.Encode(v)
+}
+
+// writeError responds with the status code matching err
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, errorStatus(err), map[string]string{"error": err.Error()})
+}
+
+// errorStatus maps application and storage errors to HTTP status codes
+func errorStatus(err error) int {
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, storage.ErrAmbiguousID):
+		return http.StatusConflict
+	case errors.Is(err, errPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, errForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, errUnsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError
+	}
+}
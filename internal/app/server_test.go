@@ -0,0 +1,103 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"taskmaster/internal/storage"
+	"testing"
+)
+
+// newTestApp returns an App for a new workspace in a temporary directory,
+// with a user configuration directory of its own
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", home)
+
+	root := t.TempDir()
+	if _, err := storage.CreateWorkspace(root); err != nil {
+		t.Fatalf("CreateWorkspace() error: %v", err)
+	}
+	s, err := storage.NewFileStorage(root)
+	if err != nil {
+		t.Fatalf("NewFileStorage() error: %v", err)
+	}
+	app := NewApp(s)
+	if err := app.Initialize(); err != nil {
+		t.Fatalf("Initialize() error: %v", err)
+	}
+	return app
+}
+
+// newTestServer returns the API server for a new workspace
+func newTestServer(t *testing.T) *apiServer {
+	return newAPIServer(newTestApp(t))
+}
+
+// createRequest builds a request creating a task, sent to host
+func createRequest(host, contentType, origin string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/api/tasks", strings.NewReader(`{"title": "Write tests"}`))
+	r.Host = host
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	if origin != "" {
+		r.Header.Set("Origin", origin)
+	}
+	return r
+}
+
+func TestServerRequestChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        string
+		contentType string
+		origin      string
+		want        int
+	}{
+		{"loopback address", "127.0.0.1:8080", "application/json", "", http.StatusCreated},
+		{"localhost", "localhost:8080", "application/json; charset=utf-8", "", http.StatusCreated},
+		{"IPv6 loopback", "[::1]:8080", "application/json", "", http.StatusCreated},
+		{"same origin", "localhost:8080", "application/json", "http://localhost:8080", http.StatusCreated},
+		{"rebound host name", "attacker.example:8080", "application/json", "", http.StatusForbidden},
+		{"foreign origin", "localhost:8080", "application/json", "http://attacker.example", http.StatusForbidden},
+		{"form body", "localhost:8080", "application/x-www-form-urlencoded", "", http.StatusUnsupportedMediaType},
+		{"plain text body", "localhost:8080", "text/plain", "", http.StatusUnsupportedMediaType},
+		{"no content type", "localhost:8080", "", "", http.StatusUnsupportedMediaType},
+	}
+
+	s := newTestServer(t)
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, createRequest(tt.host, tt.contentType, tt.origin))
+		if w.Code != tt.want {
+			t.Errorf("%s: status %d, want %d (%s)", tt.name, w.Code, tt.want, strings.TrimSpace(w.Body.String()))
+		}
+	}
+}
+
+func TestServerAllowHost(t *testing.T) {
+	s := newTestServer(t)
+	s.allowHost("Tasks.Internal")
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, createRequest("tasks.internal:8080", "application/json", "http://tasks.internal:8080"))
+	if w.Code != http.StatusCreated {
+		t.Errorf("status %d for an allowed host, want %d", w.Code, http.StatusCreated)
+	}
+}
+
+func TestServerChecksReads(t *testing.T) {
+	s := newTestServer(t)
+
+	r := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	r.Host = "attacker.example"
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status %d reading tasks through an unknown host, want %d", w.Code, http.StatusForbidden)
+	}
+}
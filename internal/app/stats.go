@@ -0,0 +1,245 @@
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"taskmaster/internal/models"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Stats summarizes the tasks in a workspace
+type Stats struct {
+	GeneratedAt           time.Time      `json:"generated_at"`
+	Total                 int            `json:"total"`
+	ByStatus              map[string]int `json:"by_status"`
+	ByPriority            map[string]int `json:"by_priority"`
+	Overdue               int            `json:"overdue"`
+	AverageOpenProgress   float64        `json:"average_open_progress"`
+	CompletedPerWeek      []WeekCount    `json:"completed_per_week"`
+	MedianCompletionHours *float64       `json:"median_completion_hours"`
+	OldestOpen            []TaskSummary  `json:"oldest_open"`
+}
+
+// WeekCount is the number of tasks completed in the week starting on WeekStart
+type WeekCount struct {
+	WeekStart string `json:"week_start"`
+	Count     int    `json:"count"`
+}
+
+// TaskSummary is a short description of a task used in reports
+type TaskSummary struct {
+	ID        int64     `json:"id"`
+	Title     string    `json:"title"`
+	Priority  string    `json:"priority"`
+	Progress  int       `json:"progress"`
+	CreatedAt time.Time `json:"created_at"`
+	AgeDays   int       `json:"age_days"`
+}
+
+// completionTime returns when a completed task was finished
+func completionTime(task *models.Task) time.Time {
+	if task.CompletionTime().IsZero() {
+		return task.UpdatedAt
+	}
+	return task.CompletionTime()
+}
+
+// weekStart returns midnight on the Monday of the week containing t
+func weekStart(t time.Time) time.Time {
+	t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+}
+
+// ComputeStats builds workspace statistics covering the given number of weeks
+// and listing up to oldest open tasks
+func ComputeStats(tasks []*models.Task, now time.Time, weeks, oldest int) *Stats {
+	stats := &Stats{
+		GeneratedAt: now,
+		Total:       len(tasks),
+		ByStatus:    map[string]int{statusInProgress: 0, statusOverdue: 0, statusCompleted: 0},
+		ByPriority:  map[string]int{},
+	}
+	for p := models.Low; p <= models.Critical; p++ {
+		stats.ByPriority[p.String()] = 0
+	}
+
+	// Completed-per-week buckets, oldest week first
+	thisWeek := weekStart(now)
+	buckets := make(map[string]int)
+	for i := weeks - 1; i >= 0; i-- {
+		key := thisWeek.AddDate(0, 0, -7*i).Format(dateKeyLayout)
+		stats.CompletedPerWeek = append(stats.CompletedPerWeek, WeekCount{WeekStart: key})
+		buckets[key] = len(stats.CompletedPerWeek) - 1
+	}
+
+	var open []*models.Task
+	var durations []time.Duration
+	progressSum := 0
+
+	for _, task := range tasks {
+		status := statusName(task)
+		stats.ByStatus[status]++
+		stats.ByPriority[task.Priority.String()]++
+
+		if task.Completed {
+			done := completionTime(task)
+			if i, ok := buckets[weekStart(done.In(now.Location())).Format(dateKeyLayout)]; ok {
+				stats.CompletedPerWeek[i].Count++
+			}
+			if !task.CreatedAt.IsZero() && done.After(task.CreatedAt) {
+				durations = append(durations, done.Sub(task.CreatedAt))
+			}
+			continue
+		}
+
+		if status == statusOverdue {
+			stats.Overdue++
+		}
+		open = append(open, task)
+		progressSum += task.Progress
+	}
+
+	if len(open) > 0 {
+		stats.AverageOpenProgress = float64(progressSum) / float64(len(open))
+	}
+
+	if len(durations) > 0 {
+		sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+		median := durations[len(durations)/2]
+		if len(durations)%2 == 0 {
+			median = (durations[len(durations)/2-1] + durations[len(durations)/2]) / 2
+		}
+		hours := median.Hours()
+		stats.MedianCompletionHours = &hours
+	}
+
+	sort.SliceStable(open, func(i, j int) bool { return open[i].CreatedAt.Before(open[j].CreatedAt) })
+	for i := 0; i < len(open) && i < oldest; i++ {
+		task := open[i]
+		stats.OldestOpen = append(stats.OldestOpen, TaskSummary{
+			ID:        task.ID,
+			Title:     task.Title,
+			Priority:  task.Priority.String(),
+			Progress:  task.Progress,
+			CreatedAt: task.CreatedAt,
+			AgeDays:   int(now.Sub(task.CreatedAt).Hours() / 24),
+		})
+	}
+
+	return stats
+}
+
+// showStats prints a summary of the workspace
+func showStats(app *App, args []string) error {
+	statsCmd := flag.NewFlagSet("stats", flag.ExitOnError)
+	outputPtr := statsCmd.String("output", "text", "Output format: text or json")
+	weeksPtr := statsCmd.Int("weeks", 8, "Number of weeks of completion history")
+	oldestPtr := statsCmd.Int("oldest", 5, "Number of oldest open tasks to list")
+
+	if err := statsCmd.Parse(args); err != nil {
+		return err
+	}
+	if *weeksPtr < 1 {
+		return fmt.Errorf("weeks must be at least 1")
+	}
+
+	tasks, err := app.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("error retrieving tasks: %w", err)
+	}
+
+	stats := ComputeStats(tasks, time.Now(), *weeksPtr, *oldestPtr)
+
+	switch *outputPtr {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(stats)
+	case "text":
+		printStats(stats)
+		return nil
+	default:
+		return fmt.Errorf("invalid output format %q: must be text or json", *outputPtr)
+	}
+}
+
+// printStats renders statistics as a human-readable dashboard
+func printStats(stats *Stats) {
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	bold := color.New(color.Bold).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	faint := color.New(color.Faint).SprintFunc()
+
+	fmt.Println(cyan("WORKSPACE STATISTICS"))
+	fmt.Println(strings.Repeat("-", 80))
+	fmt.Printf("%s: %d\n", bold("Total tasks"), stats.Total)
+	fmt.Printf("%s: %s\n", bold("Overdue"), red(fmt.Sprint(stats.Overdue)))
+	fmt.Printf("%s: %s\n", bold("Average progress of open tasks"), progressBar(int(stats.AverageOpenProgress+0.5), 20))
+	if stats.MedianCompletionHours != nil {
+		fmt.Printf("%s: %s\n", bold("Median time to completion"), formatHours(*stats.MedianCompletionHours))
+	} else {
+		fmt.Printf("%s: %s\n", bold("Median time to completion"), faint("n/a"))
+	}
+	fmt.Println()
+
+	fmt.Println(cyan("BY STATUS"))
+	for _, status := range []string{statusInProgress, statusOverdue, statusCompleted} {
+		fmt.Printf("  %-12s %s\n", statusColor(status).Sprint(fitString(status, 12)), countBar(stats.ByStatus[status], stats.Total))
+	}
+	fmt.Println()
+
+	fmt.Println(cyan("BY PRIORITY"))
+	for p := models.Critical; p >= models.Low; p-- {
+		fmt.Printf("  %s%s %s\n", p.ColoredString(), strings.Repeat(" ", 12-len(p.String())), countBar(stats.ByPriority[p.String()], stats.Total))
+	}
+	fmt.Println()
+
+	fmt.Println(cyan("COMPLETED PER WEEK"))
+	maxCount := 0
+	for _, week := range stats.CompletedPerWeek {
+		if week.Count > maxCount {
+			maxCount = week.Count
+		}
+	}
+	for _, week := range stats.CompletedPerWeek {
+		fmt.Printf("  %-12s %s\n", week.WeekStart, countBar(week.Count, maxCount))
+	}
+	fmt.Println()
+
+	fmt.Println(cyan("OLDEST OPEN TASKS"))
+	if len(stats.OldestOpen) == 0 {
+		fmt.Println("  No open tasks.")
+	}
+	for _, task := range stats.OldestOpen {
+		fmt.Printf("  %-5d %-30s %-10s %4d%%  %s\n",
+			task.ID,
+			truncateString(task.Title, 28),
+			task.Priority,
+			task.Progress,
+			faint(fmt.Sprintf("%d days old", task.AgeDays)))
+	}
+}
+
+// countBar renders a count as a bar scaled against a total
+func countBar(count, total int) string {
+	const width = 30
+	filled := 0
+	if total > 0 {
+		filled = count * width / total
+	}
+	return fmt.Sprintf("%s %d", strings.Repeat("█", filled), count)
+}
+
+// formatHours renders a duration given in hours as days and hours
+func formatHours(hours float64) string {
+	if hours < 24 {
+		return fmt.Sprintf("%.1f hours", hours)
+	}
+	return fmt.Sprintf("%.1f days", hours/24)
+}
@@ -0,0 +1,30 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package app
+
+import "errors"
+
+// terminalState is unused on platforms without termios support
+type terminalState struct{}
+
+var errNoTerminal = errors.New("interactive terminal not supported on this platform")
+
+// isTerminal always reports false on unsupported platforms
+func isTerminal(fd int) bool {
+	return false
+}
+
+// makeRaw is not supported on this platform
+func makeRaw(fd int) (*terminalState, error) {
+	return nil, errNoTerminal
+}
+
+// restoreTerminal is not supported on this platform
+func restoreTerminal(fd int, state *terminalState) error {
+	return errNoTerminal
+}
+
+// terminalSize is not supported on this platform
+func terminalSize(fd int) (int, int, error) {
+	return 0, 0, errNoTerminal
+}
@@ -0,0 +1,788 @@
+package app
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"taskmaster/internal/models"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// tuiMode represents what the interactive UI is currently doing with key input
+type tuiMode int
+
+const (
+	modeList tuiMode = iota
+	modeFilter
+	modePrompt
+	modeConfirm
+)
+
+// promptStep is a single field asked for while creating or editing a task
+type promptStep struct {
+	label string
+	value string
+}
+
+// tuiPrompt collects a sequence of field values before submitting them
+type tuiPrompt struct {
+	steps    []promptStep
+	current  int
+	onSubmit func(values []string) error
+}
+
+// tui holds the state of the interactive terminal UI
+type tui struct {
+	app       *App
+	in        *os.File
+	out       *bufio.Writer
+	tasks     []*models.Task
+	visible   []*models.Task
+	cursor    int
+	offset    int
+	filter    string
+	mode      tuiMode
+	prompt    *tuiPrompt
+	onConfirm func() error
+	message   string
+	isError   bool
+	width     int
+	height    int
+}
+
+// Interactive reports whether both stdin and stdout are attached to a terminal
+func Interactive() bool {
+	return isTerminal(int(os.Stdin.Fd())) && isTerminal(int(os.Stdout.Fd()))
+}
+
+// runTUI starts the interactive full-screen terminal UI
+func runTUI(app *App) error {
+	if !Interactive() {
+		return errors.New("the interactive UI requires a terminal")
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := makeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("failed to enter raw mode: %w", err)
+	}
+	defer restoreTerminal(fd, state)
+
+	t := &tui{
+		app: app,
+		in:  os.Stdin,
+		out: bufio.NewWriter(os.Stdout),
+	}
+
+	// Switch to the alternate screen and hide the cursor
+	t.out.WriteString("\x1b[?1049h\x1b[?25l")
+	defer func() {
+		t.out.WriteString("\x1b[?25h\x1b[?1049l")
+		t.out.Flush()
+	}()
+
+	if err := t.reload(); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 256)
+	for {
+		t.render()
+
+		n, err := t.in.Read(buf)
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+
+		for _, key := range parseKeys(buf[:n]) {
+			if quit := t.handleKey(key); quit {
+				return nil
+			}
+		}
+	}
+}
+
+// Key names produced by parseKeys for non-printable input
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyBackspace = "backspace"
+	keyCtrlC     = "ctrl-c"
+)
+
+// parseKeys converts raw terminal input into key names and printable
+// characters. A lone escape byte is the Escape key; other escape sequences
+// are arrow keys or, like function keys, ignored.
+func parseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		if b[0] == 0x1b {
+			n, key := escapeSequence(b)
+			if key != "" {
+				keys = append(keys, key)
+			}
+			b = b[n:]
+			continue
+		}
+
+		r, size := utf8.DecodeRune(b)
+		b = b[size:]
+		switch r {
+		case '\r', '\n':
+			keys = append(keys, keyEnter)
+		case 0x7f, 0x08:
+			keys = append(keys, keyBackspace)
+		case 0x03:
+			keys = append(keys, keyCtrlC)
+		default:
+			keys = append(keys, string(r))
+		}
+	}
+	return keys
+}
+
+// escapeSequence measures the escape sequence at the start of b, returning
+// its length and the key it stands for, or "" for keys the UI does not use
+func escapeSequence(b []byte) (int, string) {
+	if len(b) == 1 || b[1] == 0x1b {
+		return 1, keyEscape
+	}
+
+	switch b[1] {
+	case '[':
+		// Parameter and intermediate bytes run up to a final byte
+		for i := 2; i < len(b); i++ {
+			if b[i] >= 0x40 && b[i] <= 0x7e {
+				if i > 2 {
+					return i + 1, "" // Modified keys such as Ctrl+Up
+				}
+				return i + 1, arrowKey(b[i])
+			}
+		}
+		return len(b), ""
+	case 'O':
+		if len(b) < 3 {
+			return len(b), ""
+		}
+		return 3, arrowKey(b[2])
+	}
+	// Alt with another key
+	_, size := utf8.DecodeRune(b[1:])
+	return 1 + size, ""
+}
+
+// arrowKey returns the key named by the final byte of an arrow key sequence
+func arrowKey(final byte) string {
+	switch final {
+	case 'A':
+		return keyUp
+	case 'B':
+		return keyDown
+	}
+	return ""
+}
+
+// reload re-reads all tasks from storage and re-applies the filter
+func (t *tui) reload() error {
+	tasks, err := t.app.GetAllTasks()
+	if err != nil {
+		return fmt.Errorf("error retrieving tasks: %w", err)
+	}
+	t.tasks = tasks
+	t.applyFilter()
+	return nil
+}
+
+// applyFilter rebuilds the visible task list from the current filter text
+func (t *tui) applyFilter() {
+	t.visible = t.visible[:0]
+	needle := strings.ToLower(strings.TrimSpace(t.filter))
+	for _, task := range t.tasks {
+		if needle == "" || matchesFilter(task, needle) {
+			t.visible = append(t.visible, task)
+		}
+	}
+	if t.cursor >= len(t.visible) {
+		t.cursor = len(t.visible) - 1
+	}
+	if t.cursor < 0 {
+		t.cursor = 0
+	}
+}
+
+// matchesFilter reports whether a task matches a lowercase filter string
+func matchesFilter(task *models.Task, needle string) bool {
+	haystack := strings.ToLower(strings.Join([]string{
+		strconv.FormatInt(task.ID, 10),
+		task.Title,
+		task.Description,
+		task.Priority.String(),
+		task.Status(),
+	}, " "))
+	for _, word := range strings.Fields(needle) {
+		if !strings.Contains(haystack, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// selected returns the task under the cursor, if any
+func (t *tui) selected() *models.Task {
+	if t.cursor < 0 || t.cursor >= len(t.visible) {
+		return nil
+	}
+	return t.visible[t.cursor]
+}
+
+// setMessage shows a status message at the bottom of the screen
+func (t *tui) setMessage(msg string, isError bool) {
+	t.message = msg
+	t.isError = isError
+}
+
+// report shows the result of an action and reloads tasks on success
+func (t *tui) report(err error, success string) {
+	if err != nil {
+		t.setMessage(err.Error(), true)
+		return
+	}
+	if err := t.reload(); err != nil {
+		t.setMessage(err.Error(), true)
+		return
+	}
+	t.setMessage(success, false)
+}
+
+// handleKey dispatches a key press according to the current mode and reports whether to quit
+func (t *tui) handleKey(key string) bool {
+	if key == keyCtrlC {
+		return true
+	}
+
+	switch t.mode {
+	case modeFilter:
+		t.handleFilterKey(key)
+	case modePrompt:
+		t.handlePromptKey(key)
+	case modeConfirm:
+		t.handleConfirmKey(key)
+	default:
+		return t.handleListKey(key)
+	}
+	return false
+}
+
+// handleListKey handles keys while browsing the task list
+func (t *tui) handleListKey(key string) bool {
+	t.setMessage("", false)
+
+	switch key {
+	case "q":
+		return true
+	case keyUp, "k":
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case keyDown, "j":
+		if t.cursor < len(t.visible)-1 {
+			t.cursor++
+		}
+	case "g":
+		t.cursor = 0
+	case "G":
+		t.cursor = len(t.visible) - 1
+	case "/":
+		t.mode = modeFilter
+	case keyEscape:
+		t.filter = ""
+		t.applyFilter()
+	case "r":
+		t.report(nil, "Tasks reloaded")
+	case "n":
+		t.startCreate()
+	case "e":
+		t.startEdit()
+	case "c":
+		if task := t.selected(); task != nil {
+			t.report(t.app.CompleteTask(task.ID), fmt.Sprintf("Task %d marked as complete", task.ID))
+		}
+	case "o":
+		if task := t.selected(); task != nil {
+			t.report(t.app.ReopenTask(task.ID, 0), fmt.Sprintf("Task %d reopened", task.ID))
+		}
+	case "d":
+		t.startDelete()
+	case "+", "=":
+		t.shiftPriority(1)
+	case "-", "_":
+		t.shiftPriority(-1)
+	case ">", ".":
+		t.shiftProgress(10)
+	case "<", ",":
+		t.shiftProgress(-10)
+	case "p":
+		t.startProgress()
+	}
+	return false
+}
+
+// handleFilterKey edits the filter text, updating the list as the user types
+func (t *tui) handleFilterKey(key string) {
+	switch key {
+	case keyEnter:
+		t.mode = modeList
+	case keyEscape:
+		t.filter = ""
+		t.mode = modeList
+	case keyBackspace:
+		t.filter = trimLastRune(t.filter)
+	case keyUp, keyDown:
+		t.handleListKey(key)
+		return
+	default:
+		if isPrintable(key) {
+			t.filter += key
+		}
+	}
+	t.applyFilter()
+}
+
+// handlePromptKey edits the current prompt field and submits when all fields are filled
+func (t *tui) handlePromptKey(key string) {
+	step := &t.prompt.steps[t.prompt.current]
+
+	switch key {
+	case keyEscape:
+		t.prompt = nil
+		t.mode = modeList
+		t.setMessage("Cancelled", false)
+	case keyBackspace:
+		step.value = trimLastRune(step.value)
+	case keyEnter:
+		if t.prompt.current < len(t.prompt.steps)-1 {
+			t.prompt.current++
+			return
+		}
+
+		values := make([]string, len(t.prompt.steps))
+		for i, s := range t.prompt.steps {
+			values[i] = strings.TrimSpace(s.value)
+		}
+
+		submit := t.prompt.onSubmit
+		t.prompt = nil
+		t.mode = modeList
+		if err := submit(values); err != nil {
+			t.setMessage(err.Error(), true)
+		}
+	default:
+		if isPrintable(key) {
+			step.value += key
+		}
+	}
+}
+
+// handleConfirmKey waits for a yes/no answer
+func (t *tui) handleConfirmKey(key string) {
+	confirm := t.onConfirm
+	t.onConfirm = nil
+	t.mode = modeList
+
+	if strings.ToLower(key) != "y" {
+		t.setMessage("Cancelled", false)
+		return
+	}
+	if err := confirm(); err != nil {
+		t.setMessage(err.Error(), true)
+	}
+}
+
+// startPrompt switches to prompt mode with the given fields
+func (t *tui) startPrompt(steps []promptStep, onSubmit func(values []string) error) {
+	t.prompt = &tuiPrompt{steps: steps, onSubmit: onSubmit}
+	t.mode = modePrompt
+}
+
+// startCreate prompts for the fields of a new task
+func (t *tui) startCreate() {
+	steps := []promptStep{
+		{label: "Title"},
+		{label: "Description"},
+		{label: "Due date (YYYY-MM-DD)"},
+		{label: "Priority (0-3)", value: strconv.Itoa(int(models.Medium))},
+	}
+
+	t.startPrompt(steps, func(values []string) error {
+		dueDate, priority, err := parseTaskFields(values[2], values[3])
+		if err != nil {
+			return err
+		}
+
+		task, err := t.app.CreateTask(values[0], values[1], dueDate, priority, nil)
+		if err != nil {
+			return err
+		}
+
+		t.report(nil, fmt.Sprintf("Task created successfully with ID: %d", task.ID))
+		t.selectTask(task.ID)
+		return nil
+	})
+}
+
+// startEdit prompts for new values of the selected task's fields
+func (t *tui) startEdit() {
+	task := t.selected()
+	if task == nil {
+		return
+	}
+
+	var due string
+	if !task.DueDate.IsZero() {
+		due = task.DueDate.Format("2006-01-02")
+	}
+
+	steps := []promptStep{
+		{label: "Title", value: task.Title},
+		{label: "Description", value: task.Description},
+		{label: "Due date (YYYY-MM-DD)", value: due},
+		{label: "Priority (0-3)", value: strconv.Itoa(int(task.Priority))},
+	}
+
+	id := task.ID
+	t.startPrompt(steps, func(values []string) error {
+		dueDate, priority, err := parseTaskFields(values[2], values[3])
+		if err != nil {
+			return err
+		}
+
+		t.report(t.app.UpdateTaskDetails(id, values[0], values[1], dueDate, priority),
+			fmt.Sprintf("Task %d updated successfully", id))
+		return nil
+	})
+}
+
+// startProgress prompts for an exact progress value for the selected task
+func (t *tui) startProgress() {
+	task := t.selected()
+	if task == nil {
+		return
+	}
+
+	id := task.ID
+	steps := []promptStep{{label: "Progress (0-100)", value: strconv.Itoa(task.Progress)}}
+	t.startPrompt(steps, func(values []string) error {
+		progress, err := strconv.Atoi(values[0])
+		if err != nil {
+			return fmt.Errorf("invalid progress value: %w", err)
+		}
+
+		t.report(t.app.UpdateTaskProgress(id, progress),
+			fmt.Sprintf("Progress for task %d updated to %d%%", id, progress))
+		return nil
+	})
+}
+
+// startDelete asks for confirmation before deleting the selected task
+func (t *tui) startDelete() {
+	task := t.selected()
+	if task == nil {
+		return
+	}
+
+	id, title := task.ID, task.Title
+	t.mode = modeConfirm
+	t.onConfirm = func() error {
+		t.report(t.app.DeleteTask(id), fmt.Sprintf("Task %d deleted successfully", id))
+		return nil
+	}
+	t.setMessage(fmt.Sprintf("Delete task '%s'? (y/n)", title), false)
+}
+
+// shiftPriority raises or lowers the priority of the selected task
+func (t *tui) shiftPriority(delta int) {
+	task := t.selected()
+	if task == nil {
+		return
+	}
+
+	priority := task.Priority + models.Priority(delta)
+	if priority < models.Low || priority > models.Critical {
+		return
+	}
+
+	t.report(t.app.UpdateTaskDetails(task.ID, task.Title, task.Description, task.DueDate, priority),
+		fmt.Sprintf("Task %d priority set to %s", task.ID, priority))
+}
+
+// shiftProgress adjusts the progress of the selected task, clamped to 0-100
+func (t *tui) shiftProgress(delta int) {
+	task := t.selected()
+	if task == nil {
+		return
+	}
+
+	progress := task.Progress + delta
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 100 {
+		progress = 100
+	}
+	if progress == task.Progress {
+		return
+	}
+
+	t.report(t.app.UpdateTaskProgress(task.ID, progress),
+		fmt.Sprintf("Progress for task %d updated to %d%%", task.ID, progress))
+}
+
+// selectTask moves the cursor to the task with the given ID if it is visible
+func (t *tui) selectTask(id int64) {
+	for i, task := range t.visible {
+		if task.ID == id {
+			t.cursor = i
+			return
+		}
+	}
+}
+
+// parseTaskFields parses the due date and priority entered in a prompt
+func parseTaskFields(due, priority string) (time.Time, models.Priority, error) {
+	var dueDate time.Time
+	if due != "" {
+		parsed, err := time.Parse("2006-01-02", due)
+		if err != nil {
+			return time.Time{}, 0, fmt.Errorf("invalid date format: %w", err)
+		}
+		dueDate = parsed
+	}
+
+	p, err := strconv.Atoi(priority)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid priority: %w", err)
+	}
+
+	return dueDate, models.Priority(p), nil
+}
+
+// render draws the whole screen
+func (t *tui) render() {
+	t.width, t.height = 80, 24
+	if w, h, err := terminalSize(int(os.Stdout.Fd())); err == nil && w > 0 && h > 0 {
+		t.width, t.height = w, h
+	}
+
+	bold := color.New(color.Bold).SprintFunc()
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+
+	listWidth := t.width * 45 / 100
+	if listWidth < 30 {
+		listWidth = t.width
+	}
+	detailWidth := t.width - listWidth - 3
+	bodyHeight := t.height - 4
+
+	t.out.WriteString("\x1b[H\x1b[2J")
+
+	// Title bar
+	title := fmt.Sprintf(" TASKMASTER  %d/%d tasks", len(t.visible), len(t.tasks))
+	if t.filter != "" || t.mode == modeFilter {
+		title += "  filter: " + t.filter
+	}
+	t.out.WriteString(cyan(fitString(title, t.width)) + "\r\n")
+
+	// Keep the cursor inside the scrolling window
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+bodyHeight-1 {
+		t.offset = t.cursor - bodyHeight + 2
+	}
+
+	list := t.listLines(listWidth, bodyHeight)
+	var detail []string
+	if detailWidth >= 20 {
+		detail = t.detailLines(detailWidth)
+	}
+
+	for i := 0; i < bodyHeight; i++ {
+		line := ""
+		if i < len(list) {
+			line = list[i]
+		} else {
+			line = strings.Repeat(" ", listWidth)
+		}
+		if detailWidth >= 20 {
+			line += " │ "
+			if i < len(detail) {
+				line += detail[i]
+			}
+		}
+		t.out.WriteString(line + "\x1b[K\r\n")
+	}
+
+	// Prompt or status line
+	t.out.WriteString(strings.Repeat("─", t.width) + "\r\n")
+	switch {
+	case t.mode == modePrompt:
+		step := t.prompt.steps[t.prompt.current]
+		t.out.WriteString(bold(step.label+": ") + step.value + "█")
+	case t.mode == modeFilter:
+		t.out.WriteString(bold("/") + t.filter + "█")
+	case t.message != "" && t.isError:
+		t.out.WriteString(color.RedString(fitString(t.message, t.width)))
+	case t.message != "":
+		t.out.WriteString(color.GreenString(fitString(t.message, t.width)))
+	}
+	t.out.WriteString("\x1b[K\r\n")
+
+	help := "↑/↓ move  / filter  n new  e edit  c complete  o reopen  d delete  +/- priority  </> progress  p set progress  q quit"
+	t.out.WriteString(color.New(color.Faint).Sprint(fitString(help, t.width)))
+
+	t.out.Flush()
+}
+
+// listLines renders the task list pane
+func (t *tui) listLines(width, height int) []string {
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+
+	lines := []string{cyan(fitString(fmt.Sprintf("%-5s %-9s %-5s %s", "ID", "PRIORITY", "PROG", "TITLE"), width))}
+
+	if len(t.visible) == 0 {
+		lines = append(lines, fitString("No tasks found.", width))
+		return lines
+	}
+
+	for i := t.offset; i < len(t.visible) && len(lines) < height; i++ {
+		task := t.visible[i]
+		row := fitString(fmt.Sprintf("%-5d %-9s %3d%%  %s", task.ID, task.Priority.String(), task.Progress, task.Title), width)
+
+		switch {
+		case i == t.cursor:
+			row = "\x1b[7m" + row + "\x1b[0m"
+		case task.Completed:
+			row = color.New(color.Faint).Sprint(row)
+		case !task.DueDate.IsZero() && time.Now().After(task.DueDate):
+			row = color.RedString(row)
+		}
+		lines = append(lines, row)
+	}
+
+	return lines
+}
+
+// detailLines renders the detail pane for the selected task
+func (t *tui) detailLines(width int) []string {
+	task := t.selected()
+	if task == nil {
+		return nil
+	}
+
+	bold := color.New(color.Bold).SprintFunc()
+	field := func(name, value string) string {
+		return bold(name+": ") + fitString(value, width-len(name)-2)
+	}
+
+	due := "None"
+	if !task.DueDate.IsZero() {
+		due = task.DueDate.Format("2006-01-02")
+		if left := task.FormattedDaysLeft(); left != "" {
+			due += "  " + left
+		}
+	}
+
+	lines := []string{
+		bold(fitString(task.Title, width)),
+		"",
+		field("ID", strconv.FormatInt(task.ID, 10)),
+		bold("Priority: ") + task.Priority.ColoredString(),
+		bold("Status: ") + task.ColoredStatus(),
+		bold("Due Date: ") + due,
+		field("Progress", progressBar(task.Progress, 20)),
+		field("Created At", task.CreatedAt.Format("2006-01-02 15:04:05")),
+		field("Updated At", task.UpdatedAt.Format("2006-01-02 15:04:05")),
+		"",
+		bold("Description:"),
+	}
+
+	desc := task.Description
+	if desc == "" {
+		desc = "(none)"
+	}
+	lines = append(lines, wrapText(desc, width)...)
+
+	return lines
+}
+
+// progressBar renders a textual progress bar of the given width
+func progressBar(progress, width int) string {
+	filled := progress * width / 100
+	return fmt.Sprintf("[%s%s] %d%%", strings.Repeat("█", filled), strings.Repeat("░", width-filled), progress)
+}
+
+// fitString truncates or pads a string to exactly width runes
+func fitString(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		runes := []rune(s)
+		if width <= 3 {
+			return string(runes[:width])
+		}
+		return string(runes[:width-3]) + "..."
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// wrapText splits text into lines no wider than width runes
+func wrapText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > width {
+				lines = append(lines, line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		lines = append(lines, fitString(line, width))
+	}
+	return lines
+}
+
+// trimLastRune removes the last rune of a string
+func trimLastRune(s string) string {
+	if s == "" {
+		return s
+	}
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
+
+// isPrintable reports whether a key is printable text rather than a control sequence
+func isPrintable(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		if r < 0x20 || r == 0x7f {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,31 @@
+package app
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{"escape", "\x1b", []string{keyEscape}},
+		{"escape pressed twice", "\x1b\x1b", []string{keyEscape, keyEscape}},
+		{"arrow keys", "\x1b[A\x1bOB", []string{keyUp, keyDown}},
+		{"repeated arrow keys", "\x1b[B\x1b[B", []string{keyDown, keyDown}},
+		{"function key", "\x1bOP", nil},
+		{"delete key", "\x1b[3~", nil},
+		{"ctrl and arrow", "\x1b[1;5A", nil},
+		{"alt and letter", "\x1bx", nil},
+		{"text around a sequence", "a\x1b[Cb", []string{"a", "b"}},
+		{"typed text", "hé\r", []string{"h", "é", keyEnter}},
+		{"control keys", "\x7f\x03", []string{keyBackspace, keyCtrlC}},
+	}
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseKeys(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package app
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+	"taskmaster/internal/models"
+	"time"
+)
+
+// tasksFingerprint returns a hash of the stored state of every task, so
+// changes made by any process can be noticed by comparing fingerprints
+func (a *App) tasksFingerprint() ([32]byte, error) {
+	tasks, err := a.GetAllTasks()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	data, err := json.Marshal(tasks)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// watchTasks polls the workspace every interval and calls onChange whenever
+// its tasks differ from the previous poll, until ctx is cancelled
+func (a *App) watchTasks(ctx context.Context, interval time.Duration, onChange func()) {
+	last, _ := a.tasksFingerprint()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current, err := a.tasksFingerprint()
+			if err != nil || current == last {
+				continue
+			}
+			last = current
+			onChange()
+		}
+	}
+}
+
+// Task change actions reported by changeTracker
+const (
+	changeCreated   = "created"
+	changeUpdated   = "updated"
+	changeCompleted = "completed"
+	changeDeleted   = "deleted"
+)
+
+// taskChange describes how a task differs from the previous snapshot
+type taskChange struct {
+	Action string       `json:"action"`
+	Task   *models.Task `json:"task"`
+}
+
+// changeTracker remembers the tasks of a workspace and reports what changed
+// between snapshots. Tasks are matched by UUID, so renumbering a task shows up
+// as an update rather than a deletion and a creation.
+type changeTracker struct {
+	app   *App
+	known map[string][]byte
+	tasks map[string]*models.Task
+}
+
+// newChangeTracker takes an initial snapshot of the workspace
+func newChangeTracker(app *App) (*changeTracker, error) {
+	t := &changeTracker{app: app}
+	if _, err := t.changes(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// changes returns the tasks created, updated, completed or deleted since the
+// previous call, ordered by task ID with deletions last
+func (t *changeTracker) changes() ([]taskChange, error) {
+	tasks, err := t.app.GetAllTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	known := make(map[string][]byte, len(tasks))
+	current := make(map[string]*models.Task, len(tasks))
+	var changes []taskChange
+
+	for _, task := range tasks {
+		data, err := json.Marshal(task)
+		if err != nil {
+			return nil, err
+		}
+		known[task.UUID] = data
+		current[task.UUID] = task
+
+		if t.known == nil {
+			continue // Initial snapshot
+		}
+
+		previous, existed := t.tasks[task.UUID]
+		switch {
+		case !existed:
+			changes = append(changes, taskChange{Action: changeCreated, Task: task})
+		case string(t.known[task.UUID]) == string(data):
+			// Unchanged
+		case task.Completed && !previous.Completed:
+			changes = append(changes, taskChange{Action: changeCompleted, Task: task})
+		default:
+			changes = append(changes, taskChange{Action: changeUpdated, Task: task})
+		}
+	}
+
+	var deleted []taskChange
+	for uuid, task := range t.tasks {
+		if _, ok := current[uuid]; !ok {
+			deleted = append(deleted, taskChange{Action: changeDeleted, Task: task})
+		}
+	}
+	sort.Slice(deleted, func(i, j int) bool {
+		return deleted[i].Task.ID < deleted[j].Task.ID
+	})
+	changes = append(changes, deleted...)
+
+	t.known = known
+	t.tasks = current
+	return changes, nil
+}
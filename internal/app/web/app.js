@@ -0,0 +1,243 @@
+// TaskMaster web interface: talks to the REST API served next to it and
+// reloads whenever the server reports that tasks have changed.
+"use strict";
+
+const PRIORITIES = ["Low", "Medium", "High", "Critical"];
+const NO_DATE = "0001-01-01";
+
+const state = {
+  tasks: [],
+  editing: null, // { id, etag } of the task open in the editor
+};
+
+const $ = (id) => document.getElementById(id);
+
+async function api(method, path, body, etag) {
+  const headers = {};
+  if (body !== undefined) headers["Content-Type"] = "application/json";
+  if (etag) headers["If-Match"] = etag;
+
+  const response = await fetch("/api" + path, {
+    method,
+    headers,
+    body: body === undefined ? undefined : JSON.stringify(body),
+  });
+  if (response.status === 204) return { data: null, etag: null };
+
+  const data = await response.json();
+  if (!response.ok) {
+    const error = new Error(data.error || response.statusText);
+    error.status = response.status;
+    throw error;
+  }
+  return { data, etag: response.headers.get("ETag") };
+}
+
+function showMessage(element, text) {
+  element.textContent = text;
+  element.hidden = !text;
+}
+
+function hasDueDate(task) {
+  return !task.due_date.startsWith(NO_DATE);
+}
+
+function isOverdue(task) {
+  return !task.completed && hasDueDate(task) && new Date(task.due_date) < new Date();
+}
+
+async function load() {
+  try {
+    const { data } = await api("GET", "/tasks");
+    state.tasks = data;
+    showMessage($("message"), "");
+  } catch (error) {
+    showMessage($("message"), "Could not load tasks: " + error.message);
+  }
+  updateTagFilter();
+  render();
+}
+
+function updateTagFilter() {
+  const select = $("tag");
+  const current = select.value;
+  const tags = new Set();
+  state.tasks.forEach((task) => (task.tags || []).forEach((tag) => tags.add(tag.toLowerCase())));
+
+  select.replaceChildren(new Option("All tags", ""));
+  [...tags].sort().forEach((tag) => select.add(new Option(tag, tag)));
+  select.value = tags.has(current) ? current : "";
+}
+
+function matches(task) {
+  const search = $("search").value.trim().toLowerCase();
+  const status = $("status").value;
+  const priority = $("priority").value;
+  const tag = $("tag").value;
+
+  if (search && !(task.title + " " + task.description).toLowerCase().includes(search)) return false;
+  if (status === "open" && task.completed) return false;
+  if (status === "completed" && !task.completed) return false;
+  if (status === "overdue" && !isOverdue(task)) return false;
+  if (priority !== "" && String(task.priority) !== priority) return false;
+  if (tag && !(task.tags || []).some((t) => t.toLowerCase() === tag)) return false;
+  return true;
+}
+
+function cell(content, className) {
+  const td = document.createElement("td");
+  if (className) td.className = className;
+  if (content instanceof Node) td.append(content);
+  else td.textContent = content;
+  return td;
+}
+
+function button(label, onClick) {
+  const b = document.createElement("button");
+  b.type = "button";
+  b.textContent = label;
+  b.addEventListener("click", onClick);
+  return b;
+}
+
+function render() {
+  const rows = state.tasks.filter(matches).map((task) => {
+    const tr = document.createElement("tr");
+    if (task.completed) tr.className = "completed";
+
+    const title = document.createElement("span");
+    title.textContent = task.title;
+    const titleCell = cell(title, "title");
+    if (task.description) {
+      const desc = document.createElement("small");
+      desc.textContent = task.description;
+      titleCell.append(desc);
+    }
+
+    const progress = document.createElement("progress");
+    progress.max = 100;
+    progress.value = task.progress;
+    progress.title = task.progress + "%";
+
+    const tags = document.createElement("span");
+    (task.tags || []).forEach((tag) => {
+      const span = document.createElement("span");
+      span.className = "tag";
+      span.textContent = tag;
+      tags.append(span);
+    });
+
+    const actions = cell("", "actions");
+    actions.append(button("Edit", () => openEditor(task.id)));
+    if (!task.completed) {
+      actions.append(" ", button("Complete", () => complete(task.id)));
+    }
+
+    tr.append(
+      cell(String(task.id)),
+      titleCell,
+      cell(PRIORITIES[task.priority] || "?", "priority-" + task.priority),
+      cell(hasDueDate(task) ? task.due_date.slice(0, 10) : "", isOverdue(task) ? "overdue" : ""),
+      cell(progress),
+      cell(tags),
+      actions,
+    );
+    return tr;
+  });
+
+  $("tasks").replaceChildren(...rows);
+  $("empty").hidden = rows.length > 0;
+}
+
+async function complete(id) {
+  try {
+    await api("POST", `/tasks/${id}/complete`);
+    await load();
+  } catch (error) {
+    showMessage($("message"), `Could not complete task #${id}: ${error.message}`);
+  }
+}
+
+async function openEditor(id) {
+  const form = $("form");
+  form.reset();
+  showMessage($("form-error"), "");
+  state.editing = null;
+
+  if (id === undefined) {
+    $("form-title").textContent = "New task";
+    form.progress.value = 0;
+  } else {
+    try {
+      const { data: task, etag } = await api("GET", `/tasks/${id}`);
+      state.editing = { id, etag };
+      $("form-title").textContent = `Edit task #${id}`;
+      form.title.value = task.title;
+      form.description.value = task.description;
+      form.due_date.value = hasDueDate(task) ? task.due_date.slice(0, 10) : "";
+      form.priority.value = task.priority;
+      form.progress.value = task.progress;
+      form.tags.value = (task.tags || []).join(", ");
+    } catch (error) {
+      showMessage($("message"), `Could not open task #${id}: ${error.message}`);
+      return;
+    }
+  }
+
+  $("editor").showModal();
+  form.title.focus();
+}
+
+async function save(event) {
+  event.preventDefault();
+  const form = $("form");
+  const body = {
+    title: form.title.value.trim(),
+    description: form.description.value,
+    due_date: form.due_date.value,
+    priority: Number(form.priority.value),
+    tags: form.tags.value.split(",").map((t) => t.trim()).filter(Boolean),
+  };
+  const progress = Number(form.progress.value || 0);
+
+  try {
+    let result;
+    if (state.editing) {
+      result = await api("PATCH", `/tasks/${state.editing.id}`, body, state.editing.etag);
+    } else {
+      result = await api("POST", "/tasks", body);
+    }
+    if (result.data.progress !== progress) {
+      await api("PUT", `/tasks/${result.data.id}/progress`, { progress }, result.etag);
+    }
+    $("editor").close();
+    await load();
+  } catch (error) {
+    const text = error.status === 412
+      ? "Someone else changed this task while you were editing it. Close the editor and try again."
+      : error.message;
+    showMessage($("form-error"), text);
+  }
+}
+
+function listen() {
+  const live = $("live");
+  const events = new EventSource("/api/events");
+  events.onopen = () => {
+    live.textContent = "live";
+    live.classList.add("on");
+  };
+  events.onerror = () => {
+    live.textContent = "reconnecting";
+    live.classList.remove("on");
+  };
+  events.addEventListener("changed", load);
+}
+
+["search", "status", "priority", "tag"].forEach((id) => $(id).addEventListener("input", render));
+$("new").addEventListener("click", () => openEditor());
+$("cancel").addEventListener("click", () => $("editor").close());
+$("form").addEventListener("submit", save);
+
+load();
+listen();
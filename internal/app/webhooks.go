@@ -0,0 +1,353 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"taskmaster/internal/models"
+	"taskmaster/internal/storage"
+	"taskmaster/internal/webhook"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// eventPing is sent by 'webhook test' to check that endpoints are reachable
+const eventPing = "ping"
+
+// webhookTimeout bounds each delivery attempt
+const webhookTimeout = 10 * time.Second
+
+// webhookPayload is the JSON body sent to webhook endpoints
+type webhookPayload struct {
+	Event      string       `json:"event"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Workspace  string       `json:"workspace"`
+	Task       *models.Task `json:"task,omitempty"`
+}
+
+// webhookQueue returns the delivery queue kept in the workspace
+func (a *App) webhookQueue() *webhook.Queue {
+	return webhook.NewQueue(filepath.Join(a.Root(), storage.DirName, "webhooks"))
+}
+
+// EnableWebhooks queues a delivery to the configured endpoints whenever a
+// task is created or completed
+func (a *App) EnableWebhooks() {
+	if len(a.config.Webhooks) == 0 {
+		return
+	}
+	a.OnTaskEvent(func(event string, task *models.Task) {
+		if event == EventDeleted {
+			return // Not sent to webhooks
+		}
+		if err := a.queueWebhooks(event, task, false); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to queue %s webhook: %v\n", event, err)
+		}
+	})
+}
+
+// queueWebhooks adds a delivery of an event for every endpoint subscribed to
+// it, or for every endpoint when all is set
+func (a *App) queueWebhooks(event string, task *models.Task, all bool) error {
+	payload, err := json.Marshal(webhookPayload{
+		Event:      event,
+		OccurredAt: time.Now(),
+		Workspace:  a.Root(),
+		Task:       task,
+	})
+	if err != nil {
+		return err
+	}
+
+	queue := a.webhookQueue()
+	for _, endpoint := range a.config.Webhooks {
+		if !all && !endpoint.Wants(event) {
+			continue
+		}
+		if _, err := queue.Enqueue(event, endpoint.URL, endpoint.SigningSecret(), payload); err != nil {
+			return err
+		}
+		a.webhooksQueued.Store(true)
+	}
+	return nil
+}
+
+// queueOverdueWebhooks queues an overdue event for each task that has become
+// overdue since the last check. Reported tasks are remembered by UUID and due
+// date, so moving the due date lets a task be reported again.
+func (a *App) queueOverdueWebhooks() error {
+	wanted := false
+	for _, endpoint := range a.config.Webhooks {
+		wanted = wanted || endpoint.Wants(EventOverdue)
+	}
+	if !wanted {
+		return nil
+	}
+
+	statePath := filepath.Join(a.webhookQueue().Dir(), "overdue.json")
+	reported := make(map[string]string)
+	if data, err := os.ReadFile(statePath); err == nil {
+		json.Unmarshal(data, &reported)
+	}
+
+	tasks, err := a.GetAllTasks()
+	if err != nil {
+		return err
+	}
+
+	current := make(map[string]string)
+	changed := false
+	for _, task := range tasks {
+		if statusName(task) != statusOverdue {
+			continue
+		}
+		due := task.DueDate.Format(time.RFC3339)
+		current[task.UUID] = due
+		if reported[task.UUID] == due {
+			continue
+		}
+		if err := a.queueWebhooks(EventOverdue, task, false); err != nil {
+			return err
+		}
+		changed = true
+	}
+
+	if !changed && len(current) == len(reported) {
+		return nil
+	}
+	data, err := json.MarshalIndent(current, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(statePath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(statePath, data, 0644)
+}
+
+// DeliverWebhooks queues overdue events and sends every delivery that is due
+func (a *App) DeliverWebhooks() ([]webhook.Result, error) {
+	if len(a.config.Webhooks) == 0 {
+		return nil, nil
+	}
+	if err := a.queueOverdueWebhooks(); err != nil {
+		return nil, fmt.Errorf("failed to check for overdue tasks: %w", err)
+	}
+
+	client := &http.Client{Timeout: webhookTimeout}
+	return a.webhookQueue().Deliver(client, time.Now())
+}
+
+// flushWebhooks delivers what is due when a command finishes. Failed
+// deliveries stay queued for the next run, so only dropped ones are reported.
+func (a *App) flushWebhooks() {
+	results, err := a.DeliverWebhooks()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	for _, result := range results {
+		if result.Dropped {
+			fmt.Fprintf(os.Stderr, "Warning: giving up on %s webhook to %s after %d attempts: %v\n",
+				result.Delivery.Event, result.Delivery.URL, result.Delivery.Attempts, result.Err)
+		}
+	}
+}
+
+// runWebhooks keeps delivering webhooks in long-running commands until ctx
+// is cancelled
+func (a *App) runWebhooks(ctx context.Context, interval time.Duration) {
+	if len(a.config.Webhooks) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.flushWebhooks()
+		}
+	}
+}
+
+// webhookCommand inspects and delivers webhooks
+func webhookCommand(app *App, args []string) error {
+	if len(args) < 1 {
+		return errors.New("webhook subcommand is required: list, deliver, test or receive")
+	}
+
+	switch args[0] {
+	case "list":
+		return listWebhooks(app)
+	case "deliver":
+		return deliverWebhooks(app)
+	case "test":
+		if len(app.config.Webhooks) == 0 {
+			return errors.New("no webhooks configured in .taskmaster/config.json")
+		}
+		if err := app.queueWebhooks(eventPing, nil, true); err != nil {
+			return err
+		}
+		return deliverWebhooks(app)
+	case "receive":
+		return receiveWebhooks(args[1:])
+	default:
+		return fmt.Errorf("unknown webhook subcommand: %s", args[0])
+	}
+}
+
+// listWebhooks shows the configured endpoints and the delivery queue
+func listWebhooks(app *App) error {
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	if len(app.config.Webhooks) == 0 {
+		fmt.Println("No webhooks configured.")
+	}
+	for _, endpoint := range app.config.Webhooks {
+		events := "all events"
+		if len(endpoint.Events) > 0 {
+			events = strings.Join(endpoint.Events, ", ")
+		}
+		signed := "unsigned"
+		if endpoint.SigningSecret() != "" {
+			signed = "signed"
+		}
+		fmt.Printf("%s  (%s, %s)\n", endpoint.URL, events, signed)
+	}
+
+	queue := app.webhookQueue()
+	pending, err := queue.Pending()
+	if err != nil {
+		return err
+	}
+	failed, err := queue.Failed()
+	if err != nil {
+		return err
+	}
+
+	if len(pending) > 0 {
+		fmt.Println()
+		fmt.Println(cyan("PENDING DELIVERIES"))
+		for _, d := range pending {
+			fmt.Printf("  %-10s %s  attempts: %d  next: %s\n", d.Event, d.URL, d.Attempts, d.NextAttempt.Format("2006-01-02 15:04:05"))
+			if d.LastError != "" {
+				fmt.Printf("             last error: %s\n", red(d.LastError))
+			}
+		}
+	}
+	if len(failed) > 0 {
+		fmt.Println()
+		fmt.Println(cyan("FAILED DELIVERIES") + " (kept in " + queue.Dir() + ")")
+		for _, d := range failed {
+			fmt.Printf("  %-10s %s  %s\n", d.Event, d.URL, red(d.LastError))
+		}
+	}
+	return nil
+}
+
+// deliverWebhooks sends due deliveries now and reports each attempt
+func deliverWebhooks(app *App) error {
+	results, err := app.DeliverWebhooks()
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	if len(results) == 0 {
+		fmt.Println("Nothing to deliver")
+	}
+	for _, result := range results {
+		d := result.Delivery
+		switch {
+		case result.Err == nil:
+			fmt.Printf("%s %s to %s\n", green("✓"), d.Event, d.URL)
+		case result.Dropped:
+			fmt.Printf("%s %s to %s: %v (giving up)\n", red("✗"), d.Event, d.URL, result.Err)
+		default:
+			fmt.Printf("%s %s to %s: %v (retrying after %s)\n", red("✗"), d.Event, d.URL, result.Err,
+				d.NextAttempt.Format("15:04:05"))
+		}
+	}
+	return nil
+}
+
+// receiveWebhooks runs a local endpoint that prints the deliveries it
+// receives, for trying out webhooks without a real service
+func receiveWebhooks(args []string) error {
+	receiveCmd := flag.NewFlagSet("webhook receive", flag.ExitOnError)
+	addrPtr := receiveCmd.String("addr", "localhost:9090", "Address to listen on")
+	secretPtr := receiveCmd.String("secret", "", "Secret to check signatures against")
+	failPtr := receiveCmd.Int("fail", 0, "Answer the first N deliveries with 503 to exercise retries")
+
+	if err := receiveCmd.Parse(args); err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+	// Deliveries arrive concurrently, so each simulated failure is taken by one of them
+	var failures atomic.Int32
+	failures.Store(int32(*failPtr))
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		signature := r.Header.Get(webhook.HeaderSignature)
+		check := "unsigned"
+		switch {
+		case *secretPtr != "" && webhook.Verify(*secretPtr, body, signature):
+			check = green("valid signature")
+		case *secretPtr != "":
+			check = red("INVALID signature")
+		case signature != "":
+			check = "signed (pass --secret to verify)"
+		}
+
+		fmt.Printf("%s %s %s [%s]\n", time.Now().Format("15:04:05"), r.Header.Get(webhook.HeaderEvent),
+			r.Header.Get(webhook.HeaderDelivery), check)
+		fmt.Println(string(body))
+
+		if takeOne(&failures) {
+			fmt.Println(red("-> answered 503"))
+			http.Error(w, "simulated failure", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	fmt.Printf("Receiving webhooks on http://%s/ (press Ctrl+C to stop)\n", displayAddr(*addrPtr))
+	srv := &http.Server{Addr: *addrPtr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	return srv.ListenAndServe()
+}
+
+// takeOne decrements a counter that is above zero, reporting whether it was
+func takeOne(n *atomic.Int32) bool {
+	for {
+		current := n.Load()
+		if current <= 0 {
+			return false
+		}
+		if n.CompareAndSwap(current, current-1) {
+			return true
+		}
+	}
+}
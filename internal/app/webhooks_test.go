@@ -0,0 +1,28 @@
+package app
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestTakeOneHandsOutEachFailureOnce(t *testing.T) {
+	var failures, taken atomic.Int32
+	failures.Store(50)
+
+	var wg sync.WaitGroup
+	for range 200 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if takeOne(&failures) {
+				taken.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if taken.Load() != 50 || failures.Load() != 0 {
+		t.Errorf("%d failures taken, %d left; want 50 and 0", taken.Load(), failures.Load())
+	}
+}
@@ -0,0 +1,374 @@
+package app
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"taskmaster/internal/config"
+	"taskmaster/internal/models"
+	"taskmaster/internal/storage"
+
+	"github.com/fatih/color"
+)
+
+// workspaceFree lists the commands that work outside a workspace
+var workspaceFree = map[string]bool{
+	"help":         true,
+	"init":         true,
+	"merge-driver": true,
+	"plugins":      true,
+	"workspace":    true,
+}
+
+// allWorkspacesFlag makes list and due read every registered workspace
+const allWorkspacesFlag = "all-workspaces"
+
+// RequiresWorkspace reports whether a command line needs an existing
+// workspace. Without a command the interactive UI needs one, while the help
+// text does not; views across all registered workspaces never do.
+func RequiresWorkspace(args []string) bool {
+	if len(args) == 0 {
+		return Interactive()
+	}
+	for _, arg := range args[1:] {
+		if strings.TrimLeft(arg, "-") == allWorkspacesFlag {
+			return false
+		}
+	}
+	// The local webhook and mail receivers are stand-ins for remote services
+	if (args[0] == "webhook" || args[0] == "digest") && len(args) > 1 && args[1] == "receive" {
+		return false
+	}
+	if _, builtin := commandTable(nil)[args[0]]; builtin {
+		return !workspaceFree[args[0]]
+	}
+	// Plugins on PATH are told about the workspace only when there is one
+	return !pluginCommand(args[0])
+}
+
+// initWorkspace creates a workspace in the current directory or --dir
+func initWorkspace(app *App, args []string) error {
+	if len(args) > 0 {
+		return errors.New("init takes no arguments; use 'taskmaster --dir PATH init' to create a workspace elsewhere")
+	}
+
+	created, err := storage.CreateWorkspace(app.Root())
+	if err != nil {
+		return fmt.Errorf("failed to create workspace: %w", err)
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	if !created {
+		fmt.Printf("Workspace already exists in %s\n", app.Root())
+		return migrateWorkspace(app, nil)
+	}
+	fmt.Printf("%s Initialized empty TaskMaster workspace in %s\n", green("✓"), app.Root())
+
+	// A workspace the user created holds only what they put there
+	if _, err := setTrusted(app.Root(), true); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	return nil
+}
+
+// Migrate upgrades task files written by older versions of TaskMaster,
+// returning how many files were rewritten
+func (a *App) Migrate() (int, error) {
+	m, ok := a.storage.(interface{ Migrate() (int, error) })
+	if !ok {
+		return 0, nil
+	}
+	return m.Migrate()
+}
+
+// migrateWorkspace upgrades the workspace's files to the current format
+func migrateWorkspace(app *App, args []string) error {
+	if len(args) > 0 {
+		return errors.New("migrate takes no arguments")
+	}
+
+	upgraded, err := app.Migrate()
+	if err != nil {
+		return fmt.Errorf("failed to upgrade workspace: %w", err)
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	if upgraded == 0 {
+		fmt.Println("Workspace is up to date")
+		return nil
+	}
+	fmt.Printf("%s Upgraded %d files in %s\n", green("✓"), upgraded, app.Root())
+	return nil
+}
+
+// workspaceTask is a task together with the ID it is shown under, which is
+// prefixed with the workspace name in aggregated views
+type workspaceTask struct {
+	ID   string
+	Task *models.Task
+}
+
+// collectTasks returns the tasks of the current workspace, or of every
+// registered workspace when all is set
+func collectTasks(app *App, all bool) ([]workspaceTask, error) {
+	if all {
+		return registeredTasks()
+	}
+
+	tasks, err := app.GetAllTasks()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving tasks: %w", err)
+	}
+
+	rows := make([]workspaceTask, len(tasks))
+	for i, task := range tasks {
+		rows[i] = workspaceTask{ID: strconv.FormatInt(task.ID, 10), Task: task}
+	}
+	return rows, nil
+}
+
+// registeredTasks loads the tasks of every registered workspace, prefixing
+// their IDs with the workspace name. Workspaces that cannot be read are
+// reported and skipped.
+func registeredTasks() ([]workspaceTask, error) {
+	reg, err := config.LoadRegistry()
+	if err != nil {
+		return nil, err
+	}
+	if len(reg.Workspaces) == 0 {
+		return nil, errors.New("no workspaces registered (use 'taskmaster workspace add')")
+	}
+
+	var rows []workspaceTask
+	for _, entry := range reg.Workspaces {
+		tasks, err := workspaceTasks(entry.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping workspace %s: %v\n", entry.Name, err)
+			continue
+		}
+		for _, task := range tasks {
+			rows = append(rows, workspaceTask{ID: fmt.Sprintf("%s:%d", entry.Name, task.ID), Task: task})
+		}
+	}
+	return rows, nil
+}
+
+// workspaceTasks reads all tasks of the workspace rooted at path
+func workspaceTasks(path string) ([]*models.Task, error) {
+	store, err := storage.NewFileStorage(path)
+	if err != nil {
+		return nil, err
+	}
+	defer store.Close()
+
+	if err := store.Init(); err != nil {
+		return nil, err
+	}
+	return store.GetAllTasks()
+}
+
+// idWidth returns the column width needed for the IDs of the rows
+func idWidth(rows []workspaceTask) int {
+	width := 5
+	for _, row := range rows {
+		if len(row.ID)+1 > width {
+			width = len(row.ID) + 1
+		}
+	}
+	return width
+}
+
+// workspaceCommand manages the user-level workspace registry
+func workspaceCommand(app *App, args []string) error {
+	if len(args) < 1 {
+		return errors.New("workspace subcommand is required: add, list, remove, trust or untrust")
+	}
+
+	switch args[0] {
+	case "add":
+		return addWorkspace(app, args[1:])
+	case "list":
+		return listWorkspaces()
+	case "remove":
+		if len(args) < 2 {
+			return errors.New("workspace name is required")
+		}
+		return removeWorkspace(args[1])
+	case "trust":
+		return trustWorkspace(app, args[1:], true)
+	case "untrust":
+		return trustWorkspace(app, args[1:], false)
+	default:
+		return fmt.Errorf("unknown workspace subcommand: %s", args[0])
+	}
+}
+
+// addWorkspace registers the current workspace, or the one at the given path
+func addWorkspace(app *App, args []string) error {
+	addCmd := flag.NewFlagSet("workspace add", flag.ExitOnError)
+	namePtr := addCmd.String("name", "", "Name to show the workspace under (default: directory name)")
+
+	if err := parseInterspersed(addCmd, args); err != nil {
+		return err
+	}
+	if addCmd.NArg() > 1 {
+		return errors.New("only one workspace path can be added at a time")
+	}
+
+	root, err := storage.ResolveWorkspace(addCmd.Arg(0), app.Root())
+	if err != nil {
+		return err
+	}
+
+	name := *namePtr
+	if name == "" {
+		name = filepath.Base(root)
+	}
+
+	reg, err := config.LoadRegistry()
+	if err != nil {
+		return err
+	}
+	if err := reg.Add(name, root); err != nil {
+		return err
+	}
+	if err := reg.Save(); err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	fmt.Printf("%s Registered %s as %s\n", green("✓"), root, green(name))
+	return nil
+}
+
+// listWorkspaces shows the registered workspaces and their open task counts
+func listWorkspaces() error {
+	reg, err := config.LoadRegistry()
+	if err != nil {
+		return err
+	}
+	if len(reg.Workspaces) == 0 {
+		fmt.Println("No workspaces registered.")
+		return nil
+	}
+
+	cyan := color.New(color.FgCyan, color.Bold).SprintFunc()
+	red := color.New(color.FgRed).SprintFunc()
+
+	width := 10
+	for _, entry := range reg.Workspaces {
+		if len(entry.Name)+1 > width {
+			width = len(entry.Name) + 1
+		}
+	}
+
+	fmt.Printf("%-*s %-6s %s\n", width, cyan("NAME"), cyan("OPEN"), cyan("PATH"))
+	fmt.Println(strings.Repeat("-", 80))
+
+	for _, entry := range reg.Workspaces {
+		open := red("n/a")
+		if tasks, err := workspaceTasks(entry.Path); err == nil {
+			count := 0
+			for _, task := range tasks {
+				if !task.Completed {
+					count++
+				}
+			}
+			open = strconv.Itoa(count)
+		}
+		fmt.Printf("%-*s %-6s %s\n", width, entry.Name, open, entry.Path)
+	}
+
+	return nil
+}
+
+// removeWorkspace unregisters a workspace; its tasks are left untouched
+func removeWorkspace(nameOrPath string) error {
+	reg, err := config.LoadRegistry()
+	if err != nil {
+		return err
+	}
+
+	// Paths may be given relative to the current directory
+	if abs, err := filepath.Abs(nameOrPath); err == nil && strings.ContainsRune(nameOrPath, filepath.Separator) {
+		if root, err := storage.WorkspaceRoot(abs); err == nil {
+			nameOrPath = root
+		}
+	}
+
+	entry, err := reg.Remove(nameOrPath)
+	if err != nil {
+		return err
+	}
+	if err := reg.Save(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Unregistered %s (%s)\n", entry.Name, entry.Path)
+	return nil
+}
+
+// trusted reports whether the user trusts the workspace to run its own hook
+// scripts and plugins
+func (a *App) trusted() bool {
+	list, err := config.LoadTrustList()
+	return err == nil && list.Trusted(a.Root())
+}
+
+// trustWorkspace allows or stops the hooks and plugins of the current
+// workspace, or the one at the given path, from running
+func trustWorkspace(app *App, args []string, trust bool) error {
+	if len(args) > 1 {
+		return errors.New("only one workspace path can be given")
+	}
+	path := ""
+	if len(args) == 1 {
+		path = args[0]
+	}
+
+	root, err := storage.ResolveWorkspace(path, app.Root())
+	if err != nil {
+		return err
+	}
+	changed, err := setTrusted(root, trust)
+	if err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen).SprintFunc()
+	switch {
+	case trust && changed:
+		fmt.Printf("%s Trusted %s; its hooks and plugins will run\n", green("✓"), root)
+	case trust:
+		fmt.Printf("%s is already trusted\n", root)
+	case changed:
+		fmt.Printf("Stopped trusting %s; its hooks and plugins will not run\n", root)
+	default:
+		fmt.Printf("%s is not trusted\n", root)
+	}
+	return nil
+}
+
+// setTrusted adds the workspace rooted at root to the user's trusted
+// workspaces or removes it, reporting whether that changed anything
+func setTrusted(root string, trust bool) (bool, error) {
+	list, err := config.LoadTrustList()
+	if err != nil {
+		return false, err
+	}
+
+	var changed bool
+	if trust {
+		changed = list.Add(root)
+	} else {
+		changed = list.Remove(root)
+	}
+	if !changed {
+		return false, nil
+	}
+	return true, list.Save()
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FileName is the name of the configuration file inside the workspace directory
+const FileName = "config.json"
+
+// Config holds workspace-level settings
+type Config struct {
+	Board     BoardConfig     `json:"board"`
+	Webhooks  []WebhookConfig `json:"webhooks,omitempty"`
+	Reminders ReminderConfig  `json:"reminders"`
+	Digest    DigestConfig    `json:"digest"`
+}
+
+// BoardConfig holds settings for the kanban board view
+type BoardConfig struct {
+	// WIPLimits maps a column name (status, priority or tag) to the maximum
+	// number of tasks it should hold
+	WIPLimits map[string]int `json:"wip_limits"`
+}
+
+// WebhookConfig describes an endpoint that receives task events
+type WebhookConfig struct {
+	URL string `json:"url"`
+	// Secret signs the payloads; SecretEnv names an environment variable to
+	// read it from instead, keeping it out of the workspace
+	Secret    string `json:"secret,omitempty"`
+	SecretEnv string `json:"secret_env,omitempty"`
+	// Events limits the events sent (created, completed, overdue); empty
+	// means all of them
+	Events []string `json:"events,omitempty"`
+}
+
+// SigningSecret returns the secret used to sign payloads for the endpoint
+func (w WebhookConfig) SigningSecret() string {
+	if w.SecretEnv != "" {
+		return os.Getenv(w.SecretEnv)
+	}
+	return w.Secret
+}
+
+// Wants reports whether the endpoint subscribes to an event
+func (w WebhookConfig) Wants(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if strings.EqualFold(e, event) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReminderConfig holds settings for the reminder daemon
+type ReminderConfig struct {
+	// LeadTimes maps a priority name (low, medium, high, critical) to how
+	// long before the due date its tasks are reminded of, such as "2d" or
+	// "4h"; "off" disables reminders for the priority
+	LeadTimes map[string]string `json:"lead_times"`
+	// Notify lists how reminders are delivered: desktop, bell or command
+	Notify []string `json:"notify"`
+	// Command is run for each reminder when Notify includes "command"
+	Command string `json:"command,omitempty"`
+}
+
+// DigestConfig holds settings for the email digest
+type DigestConfig struct {
+	From string   `json:"from"`
+	To   []string `json:"to"`
+	// DueSoonDays is how many days ahead open tasks count as due soon
+	DueSoonDays int        `json:"due_soon_days"`
+	SMTP        SMTPConfig `json:"smtp"`
+}
+
+// SMTPConfig describes the mail server digests are sent through
+type SMTPConfig struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	// Password logs in to the server; PasswordEnv names an environment
+	// variable to read it from instead, keeping it out of the workspace
+	Password    string `json:"password,omitempty"`
+	PasswordEnv string `json:"password_env,omitempty"`
+	// TLS is "starttls" (the default), "tls" for TLS from the start, or
+	// "none" to send unencrypted
+	TLS string `json:"tls,omitempty"`
+}
+
+// LoginPassword returns the password used to log in to the server
+func (s SMTPConfig) LoginPassword() string {
+	if s.PasswordEnv != "" {
+		return os.Getenv(s.PasswordEnv)
+	}
+	return s.Password
+}
+
+// defaultLeadTimes apply to the priorities missing from LeadTimes
+var defaultLeadTimes = map[string]string{
+	"critical": "3d",
+	"high":     "1d",
+	"medium":   "1d",
+	"low":      "0",
+}
+
+// LeadTime returns the lead time for a priority, matched case-insensitively
+func (r ReminderConfig) LeadTime(priority string) string {
+	for name, lead := range r.LeadTimes {
+		if strings.EqualFold(name, priority) {
+			return lead
+		}
+	}
+	return defaultLeadTimes[strings.ToLower(priority)]
+}
+
+// Default returns a configuration with default values
+func Default() *Config {
+	return &Config{
+		Board: BoardConfig{
+			WIPLimits: map[string]int{},
+		},
+		Reminders: ReminderConfig{
+			LeadTimes: map[string]string{},
+			Notify:    []string{"desktop", "bell"},
+		},
+		Digest: DigestConfig{
+			DueSoonDays: 7,
+		},
+	}
+}
+
+// Load reads the configuration file from the given workspace directory,
+// falling back to defaults if it does not exist
+func Load(dir string) (*Config, error) {
+	cfg := Default()
+
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// WIPLimit returns the WIP limit for a board column, matched case-insensitively
+func (b BoardConfig) WIPLimit(column string) (int, bool) {
+	for name, limit := range b.WIPLimits {
+		if strings.EqualFold(name, column) {
+			return limit, true
+		}
+	}
+	return 0, false
+}
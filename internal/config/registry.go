@@ -0,0 +1,116 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RegistryFileName is the name of the user-level workspace registry
+const RegistryFileName = "workspaces.json"
+
+// Registry lists the workspaces a user has registered for aggregated views
+type Registry struct {
+	Workspaces []WorkspaceEntry `json:"workspaces"`
+	path       string
+}
+
+// WorkspaceEntry is a registered workspace
+type WorkspaceEntry struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+}
+
+// RegistryPath returns the location of the registry file in the user's
+// configuration directory (for example ~/.config/taskmaster on Linux)
+func RegistryPath() (string, error) {
+	return userFile(RegistryFileName)
+}
+
+// userFile returns the location of a file in the user's taskmaster
+// configuration directory
+func userFile(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(dir, "taskmaster", name), nil
+}
+
+// LoadRegistry reads the user's workspace registry, returning an empty one if
+// it does not exist yet
+func LoadRegistry() (*Registry, error) {
+	path, err := RegistryPath()
+	if err != nil {
+		return nil, err
+	}
+
+	reg := &Registry{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return reg, nil
+		}
+		return nil, fmt.Errorf("failed to read workspace registry: %w", err)
+	}
+
+	if err := json.Unmarshal(data, reg); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace registry: %w", err)
+	}
+
+	return reg, nil
+}
+
+// Save writes the registry back to the user's configuration directory
+func (r *Registry) Save() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal workspace registry: %w", err)
+	}
+
+	if err := os.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write workspace registry: %w", err)
+	}
+
+	return nil
+}
+
+// Add registers a workspace under name. Names are case-insensitive and each
+// path may only be registered once.
+func (r *Registry) Add(name, path string) error {
+	if name == "" {
+		return fmt.Errorf("workspace name cannot be empty")
+	}
+	if strings.ContainsAny(name, ": \t") {
+		return fmt.Errorf("workspace name %q cannot contain ':' or spaces", name)
+	}
+
+	for _, entry := range r.Workspaces {
+		if entry.Path == path {
+			return fmt.Errorf("%s is already registered as %q", path, entry.Name)
+		}
+		if strings.EqualFold(entry.Name, name) {
+			return fmt.Errorf("a workspace named %q is already registered (%s)", entry.Name, entry.Path)
+		}
+	}
+
+	r.Workspaces = append(r.Workspaces, WorkspaceEntry{Name: name, Path: path})
+	return nil
+}
+
+// Remove unregisters the workspace with the given name or path
+func (r *Registry) Remove(nameOrPath string) (WorkspaceEntry, error) {
+	for i, entry := range r.Workspaces {
+		if strings.EqualFold(entry.Name, nameOrPath) || entry.Path == nameOrPath {
+			r.Workspaces = append(r.Workspaces[:i], r.Workspaces[i+1:]...)
+			return entry, nil
+		}
+	}
+	return WorkspaceEntry{}, fmt.Errorf("no workspace named %q is registered", nameOrPath)
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TrustFileName is the name of the user-level list of trusted workspaces
+const TrustFileName = "trusted.json"
+
+// TrustList lists the workspaces whose hooks and plugins the user allows to
+// run. Workspaces arrive with clones and downloads, so their scripts only run
+// once the user has looked at them and trusted the workspace.
+type TrustList struct {
+	Workspaces []string `json:"workspaces"`
+	path       string
+}
+
+// TrustListPath returns the location of the trust list, next to the
+// workspace registry
+func TrustListPath() (string, error) {
+	return userFile(TrustFileName)
+}
+
+// LoadTrustList reads the user's trusted workspaces, returning an empty list
+// if there are none yet
+func LoadTrustList() (*TrustList, error) {
+	path, err := TrustListPath()
+	if err != nil {
+		return nil, err
+	}
+
+	list := &TrustList{path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return list, nil
+		}
+		return nil, fmt.Errorf("failed to read trusted workspaces: %w", err)
+	}
+
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("failed to parse trusted workspaces: %w", err)
+	}
+
+	return list, nil
+}
+
+// Save writes the trust list back to the user's configuration directory
+func (l *TrustList) Save() error {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trusted workspaces: %w", err)
+	}
+
+	if err := os.WriteFile(l.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write trusted workspaces: %w", err)
+	}
+
+	return nil
+}
+
+// Trusted reports whether the workspace rooted at path is trusted
+func (l *TrustList) Trusted(path string) bool {
+	for _, trusted := range l.Workspaces {
+		if trusted == path {
+			return true
+		}
+	}
+	return false
+}
+
+// Add trusts the workspace rooted at path, reporting false if it already was
+func (l *TrustList) Add(path string) bool {
+	if l.Trusted(path) {
+		return false
+	}
+	l.Workspaces = append(l.Workspaces, path)
+	return true
+}
+
+// Remove stops trusting the workspace rooted at path, reporting false if it
+// was not trusted
+func (l *TrustList) Remove(path string) bool {
+	for i, trusted := range l.Workspaces {
+		if trusted == path {
+			l.Workspaces = append(l.Workspaces[:i], l.Workspaces[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
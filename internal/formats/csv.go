@@ -0,0 +1,308 @@
+package formats
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"taskmaster/internal/models"
+	"time"
+)
+
+// CSV fields that columns can be mapped onto
+const (
+	CSVTitle       = "title"
+	CSVDescription = "description"
+	CSVDue         = "due"
+	CSVPriority    = "priority"
+	CSVProgress    = "progress"
+	CSVTags        = "tags"
+	CSVCompleted   = "completed"
+	CSVCreated     = "created"
+)
+
+// csvFields lists the canonical field names
+var csvFields = []string{CSVTitle, CSVDescription, CSVDue, CSVPriority, CSVProgress, CSVTags, CSVCompleted, CSVCreated}
+
+// csvFieldAliases maps accepted field names onto the canonical ones
+var csvFieldAliases = map[string]string{
+	"title":       CSVTitle,
+	"desc":        CSVDescription,
+	"description": CSVDescription,
+	"due":         CSVDue,
+	"priority":    CSVPriority,
+	"progress":    CSVProgress,
+	"tags":        CSVTags,
+	"completed":   CSVCompleted,
+	"created":     CSVCreated,
+}
+
+// DefaultCSVDateFormats are tried when no date format is configured
+var DefaultCSVDateFormats = []string{"2006-01-02", "2006/01/02", time.RFC3339}
+
+// CSVOptions controls how spreadsheet rows are mapped onto tasks
+type CSVOptions struct {
+	// Columns maps task fields to column headers; unmapped fields fall back to
+	// a column with the field's own name
+	Columns map[string]string
+	// DateFormats are Go time layouts (or YYYY/MM/DD style patterns) tried in order
+	DateFormats []string
+	// Priorities maps spreadsheet values (case-insensitive) to priorities
+	Priorities map[string]models.Priority
+	// Comma is the field delimiter, ',' if zero
+	Comma rune
+}
+
+// ParseCSVMapping parses a field=Column list such as "title=Summary,due=Deadline"
+func ParseCSVMapping(s string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	if strings.TrimSpace(s) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		field, column, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid column mapping %q: expected field=Column", pair)
+		}
+
+		canonical, known := csvFieldAliases[strings.ToLower(strings.TrimSpace(field))]
+		if !known {
+			return nil, fmt.Errorf("unknown field %q in column mapping", field)
+		}
+		mapping[canonical] = strings.TrimSpace(column)
+	}
+
+	return mapping, nil
+}
+
+// ParseCSVPriorities parses a value=priority list such as "Blocker=3,Major=high"
+func ParseCSVPriorities(s string) (map[string]models.Priority, error) {
+	priorities := make(map[string]models.Priority)
+	if strings.TrimSpace(s) == "" {
+		return priorities, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		value, priority, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid priority mapping %q: expected Value=priority", pair)
+		}
+
+		p, err := parsePriorityName(strings.TrimSpace(priority))
+		if err != nil {
+			return nil, err
+		}
+		priorities[strings.ToLower(strings.TrimSpace(value))] = p
+	}
+
+	return priorities, nil
+}
+
+// parsePriorityName parses a priority given as 0-3 or as a priority name
+func parsePriorityName(s string) (models.Priority, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < int(models.Low) || n > int(models.Critical) {
+			return 0, fmt.Errorf("priority must be between 0 and 3, got %d", n)
+		}
+		return models.Priority(n), nil
+	}
+
+	for p := models.Low; p <= models.Critical; p++ {
+		if strings.EqualFold(p.String(), s) {
+			return p, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid priority %q", s)
+}
+
+// goDateLayout converts a YYYY-MM-DD style pattern into a Go time layout;
+// Go layouts pass through unchanged
+func goDateLayout(format string) string {
+	return strings.NewReplacer(
+		"YYYY", "2006",
+		"YY", "06",
+		"MM", "01",
+		"DD", "02",
+		"hh", "15",
+		"mm", "04",
+		"ss", "05",
+	).Replace(format)
+}
+
+// DecodeCSV reads tasks from a spreadsheet export whose first row is a header.
+// Valid rows are returned even when other rows fail; each failing row is
+// reported as a *LineError carrying its line number in the file.
+func DecodeCSV(r io.Reader, opts CSVOptions) ([]*models.Task, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if opts.Comma != 0 {
+		reader.Comma = opts.Comma
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("CSV file is empty")
+		}
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns, err := resolveCSVColumns(header, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	dateFormats := opts.DateFormats
+	if len(dateFormats) == 0 {
+		dateFormats = DefaultCSVDateFormats
+	}
+
+	var tasks []*models.Task
+	var errs []error
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				errs = append(errs, &LineError{Line: parseErr.Line, Err: parseErr.Err})
+				continue
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		if isBlankRow(row) {
+			continue
+		}
+
+		task, err := csvRowToTask(row, columns, dateFormats, opts.Priorities)
+		if err != nil {
+			errs = append(errs, &LineError{Line: line, Err: err})
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, errors.Join(errs...)
+}
+
+// resolveCSVColumns finds the column index of every mapped field
+func resolveCSVColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	index := make(map[string]int)
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int)
+	for _, field := range csvFields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+
+		i, ok := index[strings.ToLower(name)]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("column %q mapped to %s not found in CSV header", name, field)
+			}
+			continue
+		}
+		columns[field] = i
+	}
+
+	if _, ok := columns[CSVTitle]; !ok {
+		return nil, errors.New("no title column found: add a \"title\" column or map one with --map title=Column")
+	}
+
+	return columns, nil
+}
+
+// csvRowToTask converts a single row into a task, validating every field
+func csvRowToTask(row []string, columns map[string]int, dateFormats []string, priorities map[string]models.Priority) (*models.Task, error) {
+	cell := func(field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	task := &models.Task{
+		Title:       cell(CSVTitle),
+		Description: cell(CSVDescription),
+		Priority:    models.Medium,
+	}
+	if task.Title == "" {
+		return nil, errors.New("title is empty")
+	}
+
+	var err error
+	if value := cell(CSVDue); value != "" {
+		if task.DueDate, err = parseCSVDate(value, dateFormats); err != nil {
+			return nil, fmt.Errorf("due: %w", err)
+		}
+	}
+	if value := cell(CSVCreated); value != "" {
+		if task.CreatedAt, err = parseCSVDate(value, dateFormats); err != nil {
+			return nil, fmt.Errorf("created: %w", err)
+		}
+	}
+
+	if value := cell(CSVPriority); value != "" {
+		if p, ok := priorities[strings.ToLower(value)]; ok {
+			task.Priority = p
+		} else if task.Priority, err = parsePriorityName(value); err != nil {
+			return nil, fmt.Errorf("priority: %w", err)
+		}
+	}
+
+	if value := strings.TrimSuffix(cell(CSVProgress), "%"); value != "" {
+		progress, err := strconv.Atoi(value)
+		if err != nil || progress < 0 || progress > 100 {
+			return nil, fmt.Errorf("progress: must be a number between 0 and 100, got %q", value)
+		}
+		task.Progress = progress
+	}
+
+	if value := cell(CSVTags); value != "" {
+		task.Tags = strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' })
+	}
+
+	switch strings.ToLower(cell(CSVCompleted)) {
+	case "", "false", "no", "n", "0":
+	case "true", "yes", "y", "1", "x", "done":
+		task.Completed = true
+		task.Progress = 100
+	default:
+		return nil, fmt.Errorf("completed: invalid value %q", cell(CSVCompleted))
+	}
+
+	return task, nil
+}
+
+// parseCSVDate parses a date using the first matching format
+func parseCSVDate(value string, formats []string) (time.Time, error) {
+	for _, format := range formats {
+		if t, err := time.Parse(goDateLayout(format), value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q does not match date format %s", value, strings.Join(formats, " or "))
+}
+
+// isBlankRow reports whether every cell of a row is empty
+func isBlankRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
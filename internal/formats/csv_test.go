@@ -0,0 +1,101 @@
+package formats
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"taskmaster/internal/models"
+	"testing"
+)
+
+func TestDecodeCSVWithMapping(t *testing.T) {
+	columns, err := ParseCSVMapping("title=Summary, due=Deadline, tags=Labels")
+	if err != nil {
+		t.Fatalf("ParseCSVMapping() error: %v", err)
+	}
+	priorities, err := ParseCSVPriorities("Blocker=critical,Major=2")
+	if err != nil {
+		t.Fatalf("ParseCSVPriorities() error: %v", err)
+	}
+
+	data := `Summary;Deadline;Priority;Labels;Progress;Completed;Notes
+"Fix login; again";10/01/2026;Blocker;auth,bug;50%;;
+Write docs;;major;docs;;yes;
+`
+	tasks, err := DecodeCSV(strings.NewReader(data), CSVOptions{
+		Columns:     columns,
+		DateFormats: []string{"DD/MM/YYYY"},
+		Priorities:  priorities,
+		Comma:       ';',
+	})
+	if err != nil {
+		t.Fatalf("DecodeCSV() error: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("decoded %d tasks, want 2", len(tasks))
+	}
+
+	first := tasks[0]
+	if first.Title != "Fix login; again" || first.Priority != models.Critical || first.Progress != 50 {
+		t.Errorf("first task = %+v", first)
+	}
+	if !first.DueDate.Equal(date(2026, 1, 10)) {
+		t.Errorf("due date = %v, want 2026-01-10", first.DueDate)
+	}
+	if !reflect.DeepEqual(first.Tags, []string{"auth", "bug"}) {
+		t.Errorf("tags = %v", first.Tags)
+	}
+
+	second := tasks[1]
+	if second.Priority != models.High || !second.Completed || second.Progress != 100 {
+		t.Errorf("second task = %+v", second)
+	}
+}
+
+func TestDecodeCSVReportsBadRowsByLine(t *testing.T) {
+	data := "title,due,progress,priority\n" +
+		"Good,2026-01-10,10,low\n" +
+		",2026-01-10,,\n" +
+		"Bad date,next week,,\n" +
+		"\n" +
+		"Bad progress,,150,\n" +
+		"Bad priority,,,urgent\n"
+
+	tasks, err := DecodeCSV(strings.NewReader(data), CSVOptions{})
+	if len(tasks) != 1 || tasks[0].Title != "Good" {
+		t.Errorf("decoded %v, want only the good row", tasks)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("DecodeCSV() error = %v, want one error per bad row", err)
+	}
+	var lines []int
+	for _, e := range joined.Unwrap() {
+		var lineErr *LineError
+		if !errors.As(e, &lineErr) {
+			t.Fatalf("error %v is not a *LineError", e)
+		}
+		lines = append(lines, lineErr.Line)
+	}
+	if want := []int{3, 4, 6, 7}; !reflect.DeepEqual(lines, want) {
+		t.Errorf("errors on lines %v, want %v", lines, want)
+	}
+}
+
+func TestDecodeCSVNeedsTitleColumn(t *testing.T) {
+	if _, err := DecodeCSV(strings.NewReader("name,due\nx,\n"), CSVOptions{}); err == nil {
+		t.Error("DecodeCSV() accepted a file without a title column")
+	}
+	if _, err := DecodeCSV(strings.NewReader("title\nx\n"), CSVOptions{Columns: map[string]string{CSVDue: "Deadline"}}); err == nil {
+		t.Error("DecodeCSV() accepted a mapping to a missing column")
+	}
+}
+
+func TestParseCSVMappingRejectsUnknownFields(t *testing.T) {
+	for _, s := range []string{"owner=Assignee", "title"} {
+		if _, err := ParseCSVMapping(s); err == nil {
+			t.Errorf("ParseCSVMapping(%q) succeeded, want an error", s)
+		}
+	}
+}
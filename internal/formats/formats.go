@@ -0,0 +1,20 @@
+// Package formats converts tasks to and from the file formats of other task tools
+package formats
+
+import "fmt"
+
+// LineError describes a problem with a single line or record of an imported file
+type LineError struct {
+	Line int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,456 @@
+package formats
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"taskmaster/internal/models"
+	"time"
+)
+
+// iCalendar date and date-time layouts (RFC 5545 section 3.3.4 and 3.3.5)
+const (
+	icalDate     = "20060102"
+	icalDateTime = "20060102T150405Z"
+	icalLocal    = "20060102T150405"
+)
+
+// icalProductID identifies TaskMaster as the producer of exported calendars
+const icalProductID = "-//TaskMaster//TaskMaster CLI//EN"
+
+// icalPriorities maps task priorities to RFC 5545 PRIORITY values (1 is highest)
+var icalPriorities = map[models.Priority]int{
+	models.Critical: 1,
+	models.High:     3,
+	models.Medium:   5,
+	models.Low:      9,
+}
+
+// priorityFromICal converts an RFC 5545 PRIORITY value to a task priority;
+// 0 means undefined and maps to the default Medium priority
+func priorityFromICal(value int) models.Priority {
+	switch {
+	case value == 1:
+		return models.Critical
+	case value >= 2 && value <= 4:
+		return models.High
+	case value >= 6:
+		return models.Low
+	default:
+		return models.Medium
+	}
+}
+
+// ICalOptions controls how tasks are written as iCalendar components
+type ICalOptions struct {
+	// Events writes tasks with a due date as all-day VEVENT entries instead of
+	// VTODO entries, for calendar applications that do not show to-dos
+	Events bool
+}
+
+// EncodeICal writes tasks as an RFC 5545 iCalendar document
+func EncodeICal(w io.Writer, tasks []*models.Task, opts ICalOptions) error {
+	iw := &icalWriter{w: bufio.NewWriter(w)}
+	now := time.Now()
+
+	iw.line("BEGIN:VCALENDAR")
+	iw.line("VERSION:2.0")
+	iw.line("PRODID:" + icalProductID)
+	iw.line("CALSCALE:GREGORIAN")
+
+	for _, task := range tasks {
+		if opts.Events {
+			if !task.DueDate.IsZero() {
+				writeVEvent(iw, task, now)
+			}
+			continue
+		}
+		writeVTodo(iw, task, now)
+	}
+
+	iw.line("END:VCALENDAR")
+
+	if iw.err != nil {
+		return iw.err
+	}
+	return iw.w.Flush()
+}
+
+// writeVTodo writes a task as a VTODO component
+func writeVTodo(iw *icalWriter, task *models.Task, now time.Time) {
+	iw.line("BEGIN:VTODO")
+	iw.line("UID:" + icalUID(task))
+	iw.line("DTSTAMP:" + now.UTC().Format(icalDateTime))
+	iw.line("SUMMARY:" + escapeICalText(task.Title))
+	if task.Description != "" {
+		iw.line("DESCRIPTION:" + escapeICalText(task.Description))
+	}
+	if !task.CreatedAt.IsZero() {
+		iw.line("CREATED:" + task.CreatedAt.UTC().Format(icalDateTime))
+	}
+	if !task.UpdatedAt.IsZero() {
+		iw.line("LAST-MODIFIED:" + task.UpdatedAt.UTC().Format(icalDateTime))
+	}
+	if !task.DueDate.IsZero() {
+		iw.line(formatICalDue("DUE", task.DueDate))
+	}
+	iw.line("PRIORITY:" + strconv.Itoa(icalPriorities[task.Priority]))
+	iw.line("PERCENT-COMPLETE:" + strconv.Itoa(task.Progress))
+
+	switch {
+	case task.Completed:
+		iw.line("STATUS:COMPLETED")
+		if completedAt := task.CompletionTime(); !completedAt.IsZero() {
+			iw.line("COMPLETED:" + completedAt.UTC().Format(icalDateTime))
+		}
+	case task.Progress > 0:
+		iw.line("STATUS:IN-PROCESS")
+	default:
+		iw.line("STATUS:NEEDS-ACTION")
+	}
+
+	if len(task.Tags) > 0 {
+		escaped := make([]string, len(task.Tags))
+		for i, tag := range task.Tags {
+			escaped[i] = escapeICalText(tag)
+		}
+		iw.line("CATEGORIES:" + strings.Join(escaped, ","))
+	}
+
+	iw.line("END:VTODO")
+}
+
+// writeVEvent writes a task's due date as an all-day VEVENT component
+func writeVEvent(iw *icalWriter, task *models.Task, now time.Time) {
+	day := time.Date(task.DueDate.Year(), task.DueDate.Month(), task.DueDate.Day(), 0, 0, 0, 0, time.UTC)
+
+	summary := task.Title
+	if task.Completed {
+		summary = "✓ " + summary
+	}
+
+	iw.line("BEGIN:VEVENT")
+	iw.line("UID:" + icalUID(task))
+	iw.line("DTSTAMP:" + now.UTC().Format(icalDateTime))
+	iw.line("DTSTART;VALUE=DATE:" + day.Format(icalDate))
+	iw.line("DTEND;VALUE=DATE:" + day.AddDate(0, 0, 1).Format(icalDate))
+	iw.line("SUMMARY:" + escapeICalText(summary))
+	if task.Description != "" {
+		iw.line("DESCRIPTION:" + escapeICalText(task.Description))
+	}
+	iw.line("PRIORITY:" + strconv.Itoa(icalPriorities[task.Priority]))
+	iw.line("TRANSP:TRANSPARENT")
+	iw.line("END:VEVENT")
+}
+
+// icalUID returns the UID of a task's calendar component
+func icalUID(task *models.Task) string {
+	if task.ExternalID != "" {
+		return task.ExternalID
+	}
+	if task.UUID != "" {
+		return task.UUID
+	}
+	return fmt.Sprintf("task-%d@taskmaster", task.ID)
+}
+
+// formatICalDue formats a due date, as a DATE value when it has no time of day
+func formatICalDue(name string, t time.Time) string {
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return name + ";VALUE=DATE:" + t.Format(icalDate)
+	}
+	return name + ":" + t.UTC().Format(icalDateTime)
+}
+
+// icalWriter writes content lines, folding them at 75 octets
+type icalWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line writes a single folded content line terminated by CRLF
+func (iw *icalWriter) line(s string) {
+	if iw.err != nil {
+		return
+	}
+
+	// Continuation lines start with a space, which counts toward the limit
+	limit := 75
+	for len(s) > limit {
+		// Fold on a UTF-8 character boundary
+		cut := limit
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		if _, iw.err = iw.w.WriteString(s[:cut] + "\r\n "); iw.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = 74
+	}
+	_, iw.err = iw.w.WriteString(s + "\r\n")
+}
+
+// escapeICalText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeICalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// unescapeICalText reverses escapeICalText
+func unescapeICalText(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
+
+// icalProperty is a parsed content line
+type icalProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseICalLine splits a content line into name, parameters and value
+func parseICalLine(line string) (icalProperty, error) {
+	prop := icalProperty{params: map[string]string{}}
+
+	// The value starts after the first colon outside quoted parameter values
+	inQuotes := false
+	colon := -1
+	for i, r := range line {
+		if r == '"' {
+			inQuotes = !inQuotes
+		}
+		if r == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("invalid content line %q", line)
+	}
+
+	prop.value = line[colon+1:]
+	parts := strings.Split(line[:colon], ";")
+	prop.name = strings.ToUpper(parts[0])
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+
+	return prop, nil
+}
+
+// parseICalTime parses a DATE or DATE-TIME value, honouring a TZID parameter
+func parseICalTime(prop icalProperty) (time.Time, error) {
+	value := prop.value
+	if prop.params["VALUE"] == "DATE" || len(value) == len(icalDate) {
+		return time.Parse(icalDate, value)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(icalDateTime, value)
+	}
+
+	loc := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	return time.ParseInLocation(icalLocal, value, loc)
+}
+
+// DecodeICal reads the VTODO components of an iCalendar document. Cancelled
+// to-dos are skipped; other component types are ignored.
+func DecodeICal(r io.Reader) ([]*models.Task, error) {
+	lines, err := unfoldICal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []*models.Task
+	var errs []error
+	var current *models.Task
+	var start int
+	var cancelled bool
+	var componentErr error
+	depth := 0
+
+	for _, l := range lines {
+		prop, err := parseICalLine(l.text)
+		if err != nil {
+			if current != nil && componentErr == nil {
+				componentErr = err
+			}
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VTODO"):
+			current = &models.Task{Priority: models.Medium}
+			start, cancelled, componentErr, depth = l.number, false, nil, 0
+			continue
+		case current == nil:
+			continue
+		case prop.name == "BEGIN":
+			depth++ // Nested component such as VALARM
+			continue
+		case prop.name == "END" && depth > 0:
+			depth--
+			continue
+		case depth > 0:
+			continue
+		case prop.name == "END" && strings.EqualFold(prop.value, "VTODO"):
+			if componentErr == nil && strings.TrimSpace(current.Title) == "" {
+				componentErr = errors.New("to-do has no SUMMARY")
+			}
+			switch {
+			case componentErr != nil:
+				errs = append(errs, &LineError{Line: start, Err: componentErr})
+			case !cancelled:
+				tasks = append(tasks, current)
+			}
+			current = nil
+			continue
+		}
+
+		if componentErr != nil {
+			continue
+		}
+
+		switch prop.name {
+		case "UID":
+			current.SetImportedID(prop.value)
+		case "SUMMARY":
+			current.Title = unescapeICalText(prop.value)
+		case "DESCRIPTION":
+			current.Description = unescapeICalText(prop.value)
+		case "CATEGORIES":
+			for _, tag := range splitICalList(prop.value) {
+				current.Tags = append(current.Tags, unescapeICalText(tag))
+			}
+		case "PRIORITY":
+			value, err := strconv.Atoi(prop.value)
+			if err != nil || value < 0 || value > 9 {
+				componentErr = fmt.Errorf("invalid PRIORITY %q", prop.value)
+				continue
+			}
+			current.Priority = priorityFromICal(value)
+		case "PERCENT-COMPLETE":
+			value, err := strconv.Atoi(prop.value)
+			if err != nil || value < 0 || value > 100 {
+				componentErr = fmt.Errorf("invalid PERCENT-COMPLETE %q", prop.value)
+				continue
+			}
+			if !current.Completed {
+				current.Progress = value
+			}
+		case "STATUS":
+			switch strings.ToUpper(prop.value) {
+			case "COMPLETED":
+				current.Completed = true
+				current.Progress = 100
+			case "CANCELLED":
+				cancelled = true
+			}
+		case "DUE", "COMPLETED", "CREATED", "LAST-MODIFIED":
+			t, err := parseICalTime(prop)
+			if err != nil {
+				componentErr = fmt.Errorf("invalid %s %q", prop.name, prop.value)
+				continue
+			}
+			switch prop.name {
+			case "DUE":
+				current.DueDate = t
+			case "COMPLETED":
+				current.CompletedAt = &t
+				current.Completed = true
+				current.Progress = 100
+			case "CREATED":
+				current.CreatedAt = t
+			case "LAST-MODIFIED":
+				current.UpdatedAt = t
+			}
+		}
+	}
+
+	if current != nil {
+		errs = append(errs, &LineError{Line: start, Err: errors.New("to-do is missing END:VTODO")})
+	}
+
+	return tasks, errors.Join(errs...)
+}
+
+// icalLine is an unfolded content line and the physical line it started on
+type icalLine struct {
+	number int
+	text   string
+}
+
+// unfoldICal reads content lines, joining folded continuation lines
+func unfoldICal(r io.Reader) ([]icalLine, error) {
+	var lines []icalLine
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if text == "" {
+			continue
+		}
+		if (text[0] == ' ' || text[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1].text += text[1:]
+			continue
+		}
+		lines = append(lines, icalLine{number: number, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read iCalendar data: %w", err)
+	}
+
+	return lines, nil
+}
+
+// splitICalList splits a comma-separated value, ignoring escaped commas
+func splitICalList(value string) []string {
+	var items []string
+	var current strings.Builder
+	escaped := false
+
+	for _, r := range value {
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ',':
+			items = append(items, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if item := current.String(); item != "" {
+		items = append(items, item)
+	}
+
+	return items
+}
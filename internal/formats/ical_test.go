@@ -0,0 +1,116 @@
+package formats
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"taskmaster/internal/models"
+	"testing"
+	"time"
+)
+
+func TestICalRoundTrip(t *testing.T) {
+	done := time.Date(2026, 1, 5, 17, 30, 0, 0, time.UTC)
+	tasks := []*models.Task{
+		{
+			UUID:        "0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b2a10",
+			Title:       "Plan launch; invite Ana, Bo & Chloé",
+			Description: "Agenda:\n- budget, \\ risks\n" + strings.Repeat("überlang ", 20),
+			Priority:    models.High,
+			DueDate:     date(2026, 1, 10),
+			Progress:    40,
+			Tags:        []string{"launch", "q1, planning"},
+			CreatedAt:   time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC),
+			UpdatedAt:   time.Date(2025, 12, 3, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			ExternalID:  "event-42@calendar.example.com",
+			Title:       "Ship it",
+			Priority:    models.Critical,
+			DueDate:     time.Date(2026, 1, 6, 15, 30, 0, 0, time.UTC),
+			Completed:   true,
+			CompletedAt: &done,
+			Progress:    100,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeICal(&buf, tasks, ICalOptions{}); err != nil {
+		t.Fatalf("EncodeICal() error: %v", err)
+	}
+	for i, line := range strings.Split(buf.String(), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line %d is %d octets long: %q", i+1, len(line), line)
+		}
+	}
+
+	got, err := DecodeICal(&buf)
+	if err != nil {
+		t.Fatalf("DecodeICal() error: %v", err)
+	}
+	if len(got) != len(tasks) {
+		t.Fatalf("decoded %d tasks, want %d", len(got), len(tasks))
+	}
+
+	for i, want := range tasks {
+		task := got[i]
+		if task.UUID != want.UUID || task.ExternalID != want.ExternalID {
+			t.Errorf("task %d: UUID %q external ID %q, want %q and %q", i, task.UUID, task.ExternalID, want.UUID, want.ExternalID)
+		}
+		if task.Title != want.Title || task.Description != want.Description {
+			t.Errorf("task %d: title %q description %q, want %q and %q", i, task.Title, task.Description, want.Title, want.Description)
+		}
+		if task.Priority != want.Priority || task.Progress != want.Progress || task.Completed != want.Completed {
+			t.Errorf("task %d: got %+v, want %+v", i, task, want)
+		}
+		if !task.DueDate.Equal(want.DueDate) {
+			t.Errorf("task %d: due %v, want %v", i, task.DueDate, want.DueDate)
+		}
+		if !task.CreatedAt.Equal(want.CreatedAt) || !task.UpdatedAt.Equal(want.UpdatedAt) {
+			t.Errorf("task %d: created %v modified %v", i, task.CreatedAt, task.UpdatedAt)
+		}
+		if !task.CompletionTime().Equal(want.CompletionTime()) {
+			t.Errorf("task %d: completed %v, want %v", i, task.CompletionTime(), want.CompletionTime())
+		}
+		if (len(task.Tags) != 0 || len(want.Tags) != 0) && !reflect.DeepEqual(task.Tags, want.Tags) {
+			t.Errorf("task %d: tags %q, want %q", i, task.Tags, want.Tags)
+		}
+	}
+}
+
+func TestICalEventsSkipTasksWithoutDueDate(t *testing.T) {
+	tasks := []*models.Task{
+		{Title: "Dated", DueDate: date(2026, 1, 10)},
+		{Title: "Undated"},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeICal(&buf, tasks, ICalOptions{Events: true}); err != nil {
+		t.Fatalf("EncodeICal() error: %v", err)
+	}
+	out := buf.String()
+	if strings.Count(out, "BEGIN:VEVENT") != 1 || strings.Contains(out, "VTODO") {
+		t.Errorf("want a single all-day event, got:\n%s", out)
+	}
+	if !strings.Contains(out, "DTSTART;VALUE=DATE:20260110\r\n") || !strings.Contains(out, "DTEND;VALUE=DATE:20260111\r\n") {
+		t.Errorf("event does not cover 2026-01-10:\n%s", out)
+	}
+}
+
+func TestDecodeICalSkipsCancelledAndReportsBadComponents(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VTODO", "UID:a", "SUMMARY:Kept", "END:VTODO",
+		"BEGIN:VTODO", "UID:b", "SUMMARY:Dropped", "STATUS:CANCELLED", "END:VTODO",
+		"BEGIN:VTODO", "UID:c", "SUMMARY:Broken", "DUE:tomorrow", "END:VTODO",
+		"END:VCALENDAR", "",
+	}, "\r\n")
+
+	tasks, err := DecodeICal(strings.NewReader(data))
+	if len(tasks) != 1 || tasks[0].Title != "Kept" {
+		t.Errorf("decoded %v, want only the kept to-do", tasks)
+	}
+	if err == nil {
+		t.Error("DecodeICal() reported no error for the invalid DUE")
+	}
+}
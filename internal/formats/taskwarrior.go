@@ -0,0 +1,277 @@
+package formats
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"taskmaster/internal/models"
+	"time"
+)
+
+// taskwarriorDate is the UTC timestamp layout used by Taskwarrior
+const taskwarriorDate = "20060102T150405Z"
+
+// Taskwarrior statuses
+const (
+	twPending   = "pending"
+	twWaiting   = "waiting"
+	twCompleted = "completed"
+	twDeleted   = "deleted"
+	twRecurring = "recurring"
+)
+
+// twTask is a task as represented in Taskwarrior's JSON export. Fields that
+// Taskwarrior has no equivalent for are stored as user-defined attributes,
+// which Taskwarrior keeps as-is on import.
+type twTask struct {
+	UUID        string         `json:"uuid"`
+	Description string         `json:"description"`
+	Status      string         `json:"status"`
+	Entry       string         `json:"entry,omitempty"`
+	Modified    string         `json:"modified,omitempty"`
+	End         string         `json:"end,omitempty"`
+	Due         string         `json:"due,omitempty"`
+	Priority    string         `json:"priority,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+	Annotations []twAnnotation `json:"annotations,omitempty"`
+
+	// User-defined attributes carrying TaskMaster-only fields
+	Notes    string `json:"taskmaster_notes,omitempty"`
+	Progress int    `json:"taskmaster_progress,omitempty"`
+	Critical bool   `json:"taskmaster_critical,omitempty"`
+}
+
+// twAnnotation is a Taskwarrior annotation
+type twAnnotation struct {
+	Entry       string `json:"entry"`
+	Description string `json:"description"`
+}
+
+// formatTWDate formats a time in Taskwarrior's layout, or returns "" for the zero time
+func formatTWDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(taskwarriorDate)
+}
+
+// parseTWDate parses a Taskwarrior timestamp, accepting RFC 3339 as well
+func parseTWDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(taskwarriorDate, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// formatTWDue formats a due date. Date-only due dates, stored as midnight
+// UTC, are written as local midnight, as Taskwarrior would record them, so
+// they show on the right day west of UTC.
+func formatTWDue(t time.Time) string {
+	if isDateOnly(t) {
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+	}
+	return formatTWDate(t)
+}
+
+// parseTWDue parses a due date, turning local midnight back into the
+// date-only form
+func parseTWDue(s string) (time.Time, error) {
+	t, err := parseTWDate(s)
+	if err != nil || t.IsZero() {
+		return t, err
+	}
+	if local := t.Local(); local.Hour() == 0 && local.Minute() == 0 && local.Second() == 0 {
+		return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return t, nil
+}
+
+// isDateOnly reports whether t is a due date without a time of day
+func isDateOnly(t time.Time) bool {
+	return !t.IsZero() && t.Location() == time.UTC && t.Hour() == 0 && t.Minute() == 0 &&
+		t.Second() == 0 && t.Nanosecond() == 0
+}
+
+// EncodeTaskwarrior writes tasks as a Taskwarrior JSON array
+func EncodeTaskwarrior(w io.Writer, tasks []*models.Task) error {
+	records := make([]twTask, 0, len(tasks))
+	for _, task := range tasks {
+		records = append(records, toTaskwarrior(task))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(records)
+}
+
+// toTaskwarrior converts a task to its Taskwarrior representation
+func toTaskwarrior(task *models.Task) twTask {
+	record := twTask{
+		UUID:        task.UUID,
+		Description: task.Title,
+		Status:      twPending,
+		Entry:       formatTWDate(task.CreatedAt),
+		Modified:    formatTWDate(task.UpdatedAt),
+		Due:         formatTWDue(task.DueDate),
+		Tags:        task.Tags,
+		Notes:       task.Description,
+		Progress:    task.Progress,
+	}
+
+	switch task.Priority {
+	case models.Critical:
+		record.Priority = "H"
+		record.Critical = true
+	case models.High:
+		record.Priority = "H"
+	case models.Medium:
+		record.Priority = "M"
+	}
+
+	if task.Completed {
+		record.Status = twCompleted
+		record.End = formatTWDate(task.CompletionTime())
+		record.Progress = 0
+	}
+
+	for _, a := range task.Annotations {
+		record.Annotations = append(record.Annotations, twAnnotation{
+			Entry:       formatTWDate(a.Entry),
+			Description: a.Description,
+		})
+	}
+
+	return record
+}
+
+// DecodeTaskwarrior reads tasks from Taskwarrior JSON, either a single array
+// as written by `task export` or one object per line as in older versions.
+// Deleted tasks and recurrence templates are skipped.
+func DecodeTaskwarrior(r io.Reader) ([]*models.Task, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Taskwarrior data: %w", err)
+	}
+
+	var records []twTask
+	var lines []int
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("failed to parse Taskwarrior JSON: %w", err)
+		}
+		for i := range records {
+			lines = append(lines, i+1)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		lineNo := 0
+		for scanner.Scan() {
+			lineNo++
+			line := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(scanner.Text()), ","))
+			if line == "" {
+				continue
+			}
+			var record twTask
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				return nil, &LineError{Line: lineNo, Err: err}
+			}
+			records = append(records, record)
+			lines = append(lines, lineNo)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read Taskwarrior data: %w", err)
+		}
+	}
+
+	var tasks []*models.Task
+	var errs []error
+	for i, record := range records {
+		if record.Status == twDeleted || record.Status == twRecurring {
+			continue
+		}
+
+		task, err := fromTaskwarrior(record)
+		if err != nil {
+			errs = append(errs, &LineError{Line: lines[i], Err: err})
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	return tasks, errors.Join(errs...)
+}
+
+// fromTaskwarrior converts a Taskwarrior record to a task
+func fromTaskwarrior(record twTask) (*models.Task, error) {
+	if strings.TrimSpace(record.Description) == "" {
+		return nil, errors.New("task has no description")
+	}
+
+	task := &models.Task{
+		Title:       record.Description,
+		Description: record.Notes,
+		Tags:        record.Tags,
+		Progress:    record.Progress,
+	}
+	task.SetImportedID(record.UUID)
+
+	switch strings.ToUpper(record.Priority) {
+	case "H":
+		task.Priority = models.High
+		if record.Critical {
+			task.Priority = models.Critical
+		}
+	case "M":
+		task.Priority = models.Medium
+	default:
+		task.Priority = models.Low
+	}
+
+	var err error
+	if task.CreatedAt, err = parseTWDate(record.Entry); err != nil {
+		return nil, fmt.Errorf("entry: %w", err)
+	}
+	if task.UpdatedAt, err = parseTWDate(record.Modified); err != nil {
+		return nil, fmt.Errorf("modified: %w", err)
+	}
+	if task.DueDate, err = parseTWDue(record.Due); err != nil {
+		return nil, fmt.Errorf("due: %w", err)
+	}
+
+	switch record.Status {
+	case twCompleted:
+		task.Completed = true
+		task.Progress = 100
+		end, err := parseTWDate(record.End)
+		if err != nil {
+			return nil, fmt.Errorf("end: %w", err)
+		}
+		if !end.IsZero() {
+			task.CompletedAt = &end
+		}
+	case twPending, twWaiting, "":
+	default:
+		return nil, fmt.Errorf("unknown status %q", record.Status)
+	}
+
+	for _, a := range record.Annotations {
+		entry, err := parseTWDate(a.Entry)
+		if err != nil {
+			return nil, fmt.Errorf("annotation: %w", err)
+		}
+		task.Annotations = append(task.Annotations, models.Annotation{Entry: entry, Description: a.Description})
+	}
+
+	return task, nil
+}
@@ -0,0 +1,121 @@
+package formats
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"taskmaster/internal/models"
+	"testing"
+	"time"
+)
+
+// inZone runs the test with time.Local set to a fixed offset from UTC
+func inZone(t *testing.T, hours int) {
+	t.Helper()
+	local := time.Local
+	time.Local = time.FixedZone("test", hours*3600)
+	t.Cleanup(func() { time.Local = local })
+}
+
+func TestTaskwarriorRoundTrip(t *testing.T) {
+	done := time.Date(2026, 1, 5, 17, 30, 0, 0, time.UTC)
+	tasks := []*models.Task{
+		{
+			UUID:        "0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b2a10",
+			Title:       "Write the release notes",
+			Description: "Cover the new import formats",
+			Priority:    models.Critical,
+			DueDate:     date(2026, 1, 10),
+			Progress:    40,
+			Tags:        []string{"docs", "release"},
+			CreatedAt:   time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC),
+			UpdatedAt:   time.Date(2025, 12, 3, 9, 0, 0, 0, time.UTC),
+			Annotations: []models.Annotation{{Entry: time.Date(2025, 12, 2, 9, 0, 0, 0, time.UTC), Description: "Draft shared"}},
+		},
+		{
+			UUID:        "1c8d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f",
+			Title:       "Ship it",
+			Priority:    models.Low,
+			DueDate:     time.Date(2026, 1, 6, 15, 30, 0, 0, time.UTC),
+			Completed:   true,
+			CompletedAt: &done,
+			Progress:    100,
+			CreatedAt:   time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC),
+			UpdatedAt:   done,
+		},
+	}
+
+	for _, zone := range []int{0, -8, 9} {
+		inZone(t, zone)
+
+		var buf bytes.Buffer
+		if err := EncodeTaskwarrior(&buf, tasks); err != nil {
+			t.Fatalf("EncodeTaskwarrior() error: %v", err)
+		}
+		got, err := DecodeTaskwarrior(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("DecodeTaskwarrior() error: %v", err)
+		}
+		if len(got) != len(tasks) {
+			t.Fatalf("UTC%+d: decoded %d tasks, want %d", zone, len(got), len(tasks))
+		}
+
+		for i, want := range tasks {
+			task := got[i]
+			if task.UUID != want.UUID || task.Title != want.Title || task.Description != want.Description ||
+				task.Priority != want.Priority || task.Progress != want.Progress || task.Completed != want.Completed {
+				t.Errorf("UTC%+d: task %d: got %+v, want %+v", zone, i, task, want)
+			}
+			if !task.DueDate.Equal(want.DueDate) {
+				t.Errorf("UTC%+d: task %d: due %v, want %v", zone, i, task.DueDate, want.DueDate)
+			}
+			if !task.CreatedAt.Equal(want.CreatedAt) || !task.UpdatedAt.Equal(want.UpdatedAt) {
+				t.Errorf("UTC%+d: task %d: entry %v modified %v, want %v and %v",
+					zone, i, task.CreatedAt, task.UpdatedAt, want.CreatedAt, want.UpdatedAt)
+			}
+			if !task.CompletionTime().Equal(want.CompletionTime()) {
+				t.Errorf("UTC%+d: task %d: end %v, want %v", zone, i, task.CompletionTime(), want.CompletionTime())
+			}
+			if !reflect.DeepEqual(task.Tags, want.Tags) || len(task.Annotations) != len(want.Annotations) {
+				t.Errorf("UTC%+d: task %d: tags %v annotations %v", zone, i, task.Tags, task.Annotations)
+			}
+		}
+	}
+}
+
+func TestTaskwarriorDueDateIsLocalMidnight(t *testing.T) {
+	inZone(t, -8)
+
+	record := toTaskwarrior(&models.Task{Title: "Pay rent", DueDate: date(2026, 3, 1)})
+	if record.Due != "20260301T080000Z" {
+		t.Errorf("due = %s, want midnight in UTC-8 (20260301T080000Z)", record.Due)
+	}
+
+	// Taskwarrior records "due:2026-03-01" the same way
+	var records []twTask
+	data := `[{"uuid":"0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b2a10","description":"Pay rent","status":"pending","due":"20260301T080000Z"}]`
+	if err := json.Unmarshal([]byte(data), &records); err != nil {
+		t.Fatal(err)
+	}
+	task, err := fromTaskwarrior(records[0])
+	if err != nil {
+		t.Fatalf("fromTaskwarrior() error: %v", err)
+	}
+	if !task.DueDate.Equal(date(2026, 3, 1)) {
+		t.Errorf("due date = %v, want 2026-03-01", task.DueDate)
+	}
+}
+
+func TestDecodeTaskwarriorSkipsDeletedAndRecurring(t *testing.T) {
+	data := `{"uuid":"0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b2a10","description":"kept","status":"pending"}
+{"uuid":"1c8d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f","description":"gone","status":"deleted"}
+{"uuid":"2d9e3f4a-5b6c-4d7e-8f9a-0b1c2d3e4f5a","description":"template","status":"recurring"}
+`
+	tasks, err := DecodeTaskwarrior(bytes.NewReader([]byte(data)))
+	if err != nil {
+		t.Fatalf("DecodeTaskwarrior() error: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != "kept" {
+		t.Errorf("decoded %v, want only the pending task", tasks)
+	}
+}
@@ -0,0 +1,241 @@
+package formats
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"taskmaster/internal/models"
+	"time"
+)
+
+// todoTxtDate is the date layout used by todo.txt
+const todoTxtDate = "2006-01-02"
+
+// todoTxtPriorities maps task priorities to todo.txt priority letters
+var todoTxtPriorities = map[models.Priority]byte{
+	models.Critical: 'A',
+	models.High:     'B',
+	models.Medium:   'C',
+	models.Low:      'D',
+}
+
+// priorityFromLetter converts a todo.txt priority letter to a task priority;
+// letters below D are all treated as low priority
+func priorityFromLetter(letter byte) models.Priority {
+	for p, l := range todoTxtPriorities {
+		if l == letter {
+			return p
+		}
+	}
+	return models.Low
+}
+
+// EncodeTodoTxt writes tasks in todo.txt format, one task per line
+func EncodeTodoTxt(w io.Writer, tasks []*models.Task) error {
+	for _, task := range tasks {
+		if _, err := fmt.Fprintln(w, TodoTxtLine(task)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TodoTxtLine renders a single task as a todo.txt line
+func TodoTxtLine(task *models.Task) string {
+	var parts []string
+	letter := string(todoTxtPriorities[task.Priority])
+
+	// Completed tasks lose their priority position, so it moves to a pri: tag
+	if task.Completed {
+		parts = append(parts, "x")
+		completedAt := task.CompletionTime()
+		if completedAt.IsZero() {
+			completedAt = task.UpdatedAt
+		}
+		if !completedAt.IsZero() {
+			parts = append(parts, completedAt.Format(todoTxtDate))
+		}
+	} else {
+		parts = append(parts, "("+letter+")")
+	}
+
+	if !task.CreatedAt.IsZero() {
+		parts = append(parts, task.CreatedAt.Format(todoTxtDate))
+	}
+
+	title := strings.Join(strings.Fields(task.Title), " ")
+	if !plainTodoTxtTitle(title, task.CreatedAt.IsZero()) {
+		// Words todo.txt would read as tags, dates or extensions are kept
+		// by storing the whole title as an extension
+		title = "title:" + url.PathEscape(task.Title)
+	}
+	parts = append(parts, title)
+
+	for _, tag := range task.Tags {
+		tag = strings.Join(strings.Fields(tag), "_")
+		if strings.HasPrefix(tag, "@") {
+			parts = append(parts, tag)
+		} else {
+			parts = append(parts, "+"+tag)
+		}
+	}
+
+	if !task.DueDate.IsZero() {
+		parts = append(parts, "due:"+task.DueDate.Format(todoTxtDate))
+	}
+	if task.Completed {
+		parts = append(parts, "pri:"+letter)
+	} else if task.Progress > 0 {
+		parts = append(parts, "progress:"+strconv.Itoa(task.Progress))
+	}
+	if task.Description != "" {
+		parts = append(parts, "desc:"+url.PathEscape(task.Description))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// plainTodoTxtTitle reports whether a title reads back unchanged when written
+// as plain words: none of them may look like a tag or a key:value extension,
+// and the first may not look like a priority, or like a creation date when
+// the task has none
+func plainTodoTxtTitle(title string, noCreationDate bool) bool {
+	words := strings.Fields(title)
+	if len(words) == 0 {
+		return false
+	}
+	first := words[0]
+	if len(first) == 3 && first[0] == '(' && first[2] == ')' {
+		return false
+	}
+	if _, err := time.Parse(todoTxtDate, first); err == nil && noCreationDate {
+		return false
+	}
+	for _, word := range words {
+		if len(word) > 1 && (word[0] == '+' || word[0] == '@') {
+			return false
+		}
+		if key, _, ok := strings.Cut(word, ":"); ok && key != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// DecodeTodoTxt reads tasks from todo.txt format. Blank lines are skipped and
+// every malformed line is reported as a *LineError.
+func DecodeTodoTxt(r io.Reader) ([]*models.Task, error) {
+	var tasks []*models.Task
+	var errs []error
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		task, err := ParseTodoTxtLine(line)
+		if err != nil {
+			errs = append(errs, &LineError{Line: lineNo, Err: err})
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read todo.txt data: %w", err)
+	}
+
+	return tasks, errors.Join(errs...)
+}
+
+// ParseTodoTxtLine parses a single todo.txt line into a task
+func ParseTodoTxtLine(line string) (*models.Task, error) {
+	task := &models.Task{Priority: models.Medium}
+	tokens := strings.Fields(line)
+
+	// Completion marker and completion date
+	if len(tokens) > 0 && tokens[0] == "x" {
+		task.Completed = true
+		task.Progress = 100
+		tokens = tokens[1:]
+		if len(tokens) > 0 {
+			if date, err := time.Parse(todoTxtDate, tokens[0]); err == nil {
+				task.CompletedAt = &date
+				tokens = tokens[1:]
+			}
+		}
+	}
+
+	// Priority
+	if len(tokens) > 0 && len(tokens[0]) == 3 && tokens[0][0] == '(' && tokens[0][2] == ')' &&
+		tokens[0][1] >= 'A' && tokens[0][1] <= 'Z' {
+		task.Priority = priorityFromLetter(tokens[0][1])
+		tokens = tokens[1:]
+	}
+
+	// Creation date
+	if len(tokens) > 0 {
+		if date, err := time.Parse(todoTxtDate, tokens[0]); err == nil {
+			task.CreatedAt = date
+			tokens = tokens[1:]
+		}
+	}
+
+	var words []string
+	var title *string
+	for _, token := range tokens {
+		switch {
+		case len(token) > 1 && token[0] == '+':
+			task.Tags = append(task.Tags, token[1:])
+		case len(token) > 1 && token[0] == '@':
+			task.Tags = append(task.Tags, token)
+		case strings.HasPrefix(token, "due:"):
+			date, err := time.Parse(todoTxtDate, strings.TrimPrefix(token, "due:"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid due date %q", token)
+			}
+			task.DueDate = date
+		case strings.HasPrefix(token, "pri:") && len(token) == 5:
+			task.Priority = priorityFromLetter(token[4])
+		case strings.HasPrefix(token, "progress:"):
+			progress, err := strconv.Atoi(strings.TrimPrefix(token, "progress:"))
+			if err != nil || progress < 0 || progress > 100 {
+				return nil, fmt.Errorf("invalid progress %q", token)
+			}
+			if !task.Completed {
+				task.Progress = progress
+			}
+		case strings.HasPrefix(token, "title:"):
+			value, err := url.PathUnescape(strings.TrimPrefix(token, "title:"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid title %q", token)
+			}
+			title = &value
+		case strings.HasPrefix(token, "desc:"):
+			desc, err := url.PathUnescape(strings.TrimPrefix(token, "desc:"))
+			if err != nil {
+				return nil, fmt.Errorf("invalid description %q", token)
+			}
+			task.Description = desc
+		default:
+			words = append(words, token)
+		}
+	}
+
+	task.Title = strings.Join(words, " ")
+	if title != nil {
+		task.Title = *title
+	}
+	if task.Title == "" {
+		return nil, errors.New("task has no title")
+	}
+
+	return task, nil
+}
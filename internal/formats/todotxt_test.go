@@ -0,0 +1,100 @@
+package formats
+
+import (
+	"bytes"
+	"reflect"
+	"taskmaster/internal/models"
+	"testing"
+	"time"
+)
+
+// date returns midnight UTC on the given day, as due dates are stored
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestTodoTxtRoundTrip(t *testing.T) {
+	done := date(2026, 1, 5)
+	tasks := []*models.Task{
+		{
+			Title:       "Write the release notes",
+			Description: "Cover the new import formats\nand the API",
+			Priority:    models.High,
+			DueDate:     date(2026, 1, 10),
+			Progress:    40,
+			Tags:        []string{"docs", "@home"},
+			CreatedAt:   date(2025, 12, 1),
+		},
+		{
+			Title:       "Email @bob about +launch due:2026-01-01",
+			Priority:    models.Critical,
+			CreatedAt:   date(2025, 12, 2),
+			Completed:   true,
+			CompletedAt: &done,
+			Progress:    100,
+		},
+		{Title: "2026-02-01 planning at 10:30", Priority: models.Low},
+		{Title: "(A) is not a priority", Priority: models.Medium, Completed: true, CompletedAt: &done, Progress: 100},
+		{Title: "title:taken desc:too", Priority: models.Medium},
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeTodoTxt(&buf, tasks); err != nil {
+		t.Fatalf("EncodeTodoTxt() error: %v", err)
+	}
+	got, err := DecodeTodoTxt(&buf)
+	if err != nil {
+		t.Fatalf("DecodeTodoTxt() error: %v\n%s", err, buf.String())
+	}
+	if len(got) != len(tasks) {
+		t.Fatalf("decoded %d tasks, want %d", len(got), len(tasks))
+	}
+
+	for i, want := range tasks {
+		task := got[i]
+		if task.Title != want.Title {
+			t.Errorf("task %d: title %q, want %q", i, task.Title, want.Title)
+		}
+		if task.Description != want.Description || task.Priority != want.Priority ||
+			task.Progress != want.Progress || task.Completed != want.Completed {
+			t.Errorf("task %d: got %+v, want %+v", i, task, want)
+		}
+		if !task.DueDate.Equal(want.DueDate) || !task.CreatedAt.Equal(want.CreatedAt) {
+			t.Errorf("task %d: due %v created %v, want %v and %v", i, task.DueDate, task.CreatedAt, want.DueDate, want.CreatedAt)
+		}
+		if !task.CompletionTime().Equal(want.CompletionTime()) {
+			t.Errorf("task %d: completed at %v, want %v", i, task.CompletionTime(), want.CompletionTime())
+		}
+		if len(task.Tags) != 0 || len(want.Tags) != 0 {
+			if !reflect.DeepEqual(task.Tags, want.Tags) {
+				t.Errorf("task %d: tags %v, want %v", i, task.Tags, want.Tags)
+			}
+		}
+	}
+}
+
+func TestTodoTxtLineKeepsPlainTitlesReadable(t *testing.T) {
+	task := &models.Task{Title: "Call the bank", Priority: models.Medium, Tags: []string{"errands"}}
+	if got, want := TodoTxtLine(task), "(C) Call the bank +errands"; got != want {
+		t.Errorf("TodoTxtLine() = %q, want %q", got, want)
+	}
+}
+
+func TestParseTodoTxtLine(t *testing.T) {
+	task, err := ParseTodoTxtLine("x 2026-01-05 2025-12-01 Pay rent +home @phone due:2026-01-01 pri:A")
+	if err != nil {
+		t.Fatalf("ParseTodoTxtLine() error: %v", err)
+	}
+	if task.Title != "Pay rent" || !task.Completed || task.Priority != models.Critical {
+		t.Errorf("got %+v", task)
+	}
+	if !reflect.DeepEqual(task.Tags, []string{"home", "@phone"}) {
+		t.Errorf("tags = %v", task.Tags)
+	}
+
+	for _, line := range []string{"", "+tag @context", "Fix due:tomorrow", "Fix progress:150"} {
+		if _, err := ParseTodoTxtLine(line); err == nil {
+			t.Errorf("ParseTodoTxtLine(%q) succeeded, want an error", line)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+// Package git talks to the local git repository containing a workspace
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNotRepository is returned when a directory is not inside a git work tree
+var ErrNotRepository = errors.New("not a git repository")
+
+// run executes git in dir and returns its trimmed standard output
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "not a git repository") {
+			return "", ErrNotRepository
+		}
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", fmt.Errorf("git %s: %s", args[0], msg)
+	}
+
+	return strings.TrimRight(stdout.String(), "\n"), nil
+}
+
+// IsRepository reports whether dir is inside a git work tree
+func IsRepository(dir string) bool {
+	out, err := run(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// HooksDir returns the directory git reads hooks from, honouring core.hooksPath
+func HooksDir(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(dir, out)
+	}
+	return out, nil
+}
+
+// HeadMessage returns the hash and full message of the latest commit
+func HeadMessage(dir string) (string, string, error) {
+	out, err := run(dir, "log", "-1", "--format=%H%n%B")
+	if err != nil {
+		return "", "", err
+	}
+	hash, message, _ := strings.Cut(out, "\n")
+	return hash, message, nil
+}
+
+// Commit is a commit summary
+type Commit struct {
+	Hash    string
+	Date    string
+	Author  string
+	Subject string
+}
+
+// CommitsReferencing lists commits whose message mentions #id, newest first
+func CommitsReferencing(dir string, id int64) ([]Commit, error) {
+	out, err := run(dir, "log", "--extended-regexp", "--regexp-ignore-case",
+		fmt.Sprintf("--grep=#%d([^0-9]|$)", id),
+		"--date=short", "--format=%h%x09%ad%x09%an%x09%s")
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.SplitN(line, "\t", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		commits = append(commits, Commit{Hash: fields[0], Date: fields[1], Author: fields[2], Subject: fields[3]})
+	}
+	return commits, nil
+}
+
+// hookMarker identifies hook scripts written by TaskMaster
+const hookMarker = "# Installed by taskmaster git-hook install"
+
+// Hooks lists the hooks TaskMaster installs
+var Hooks = []string{"commit-msg", "post-commit"}
+
+// hookScript returns the shell script for a hook, calling the given binary on
+// the workspace in dir, which need not be at the top of the repository
+func hookScript(hook, binary, dir string) string {
+	args := ""
+	if hook == "commit-msg" {
+		args = ` "$1"`
+	}
+	return fmt.Sprintf("#!/bin/sh\n%s\nexec %s --dir %s git-hook run %s%s\n",
+		hookMarker, shellQuote(binary), shellQuote(dir), hook, args)
+}
+
+// shellQuote quotes a string for use in a POSIX shell script
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// InstallHooks writes TaskMaster's hooks into the repository containing dir.
+// Existing hooks not written by TaskMaster are only replaced when force is set.
+func InstallHooks(dir, binary string, force bool) ([]string, error) {
+	hooksDir, err := HooksDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	// Check every hook before writing any of them
+	for _, hook := range Hooks {
+		path := filepath.Join(hooksDir, hook)
+		if data, err := os.ReadFile(path); err == nil && !bytes.Contains(data, []byte(hookMarker)) && !force {
+			return nil, fmt.Errorf("%s already exists and was not installed by taskmaster (use --force to replace it)", path)
+		}
+	}
+
+	var installed []string
+	for _, hook := range Hooks {
+		path := filepath.Join(hooksDir, hook)
+		if err := os.WriteFile(path, []byte(hookScript(hook, binary, dir)), 0755); err != nil {
+			return installed, fmt.Errorf("failed to write %s hook: %w", hook, err)
+		}
+		installed = append(installed, path)
+	}
+
+	return installed, nil
+}
+
+// UninstallHooks removes the hooks written by TaskMaster, leaving others alone
+func UninstallHooks(dir string) ([]string, error) {
+	hooksDir, err := HooksDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, hook := range Hooks {
+		path := filepath.Join(hooksDir, hook)
+		data, err := os.ReadFile(path)
+		if err != nil || !bytes.Contains(data, []byte(hookMarker)) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove %s hook: %w", hook, err)
+		}
+		removed = append(removed, path)
+	}
+
+	return removed, nil
+}
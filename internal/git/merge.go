@@ -0,0 +1,60 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mergeDriverName is the name the task merge driver is registered under
+const mergeDriverName = "taskmaster"
+
+// mergeAttribute routes task files to the merge driver
+const mergeAttribute = ".taskmaster/task_*.json merge=" + mergeDriverName
+
+// InstallMergeDriver registers the task merge driver in the repository's
+// config and routes task files to it through .gitattributes in the work tree
+// root. It returns the path of the attributes file.
+func InstallMergeDriver(dir, binary string) (string, error) {
+	top, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := run(dir, "config", "merge."+mergeDriverName+".name", "TaskMaster task merge driver"); err != nil {
+		return "", err
+	}
+	driver := shellQuote(binary) + " merge-driver run %O %A %B"
+	if _, err := run(dir, "config", "merge."+mergeDriverName+".driver", driver); err != nil {
+		return "", err
+	}
+
+	// The pattern is relative to the work tree root, so point it at the workspace
+	pattern := mergeAttribute
+	if rel, err := filepath.Rel(top, dir); err == nil && rel != "." {
+		pattern = filepath.ToSlash(rel) + "/" + mergeAttribute
+	}
+
+	path := filepath.Join(top, ".gitattributes")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == pattern {
+			return path, nil
+		}
+	}
+
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += pattern + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return "", fmt.Errorf("failed to write .gitattributes: %w", err)
+	}
+
+	return path, nil
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ActionKind is what a commit message asks to do with a task
+type ActionKind int
+
+const (
+	// ActionComplete marks the task as completed
+	ActionComplete ActionKind = iota
+	// ActionProgress sets the task's progress
+	ActionProgress
+)
+
+// Action is a task update requested by a commit message
+type Action struct {
+	Kind     ActionKind
+	TaskID   int64
+	Progress int
+}
+
+// Patterns for task references in commit messages, e.g. "fixes #12",
+// "closes #3, #4" and "progress #12 60%"
+var (
+	completePattern = regexp.MustCompile(`(?i)\b(?:fix(?:es|ed)?|close[sd]?|resolve[sd]?|complete[sd]?)\s+(#\d+(?:\s*(?:,|and)\s*#\d+)*)`)
+	progressPattern = regexp.MustCompile(`(?i)\bprogress\s+#(\d+)\s+(\d{1,3})\s*%`)
+	idPattern       = regexp.MustCompile(`#(\d+)`)
+)
+
+// ParseMessage extracts the task actions requested by a commit message. A task
+// that is both completed and given a progress value is only completed.
+func ParseMessage(message string) []Action {
+	var actions []Action
+	completed := make(map[int64]bool)
+
+	for _, match := range completePattern.FindAllStringSubmatch(message, -1) {
+		for _, ref := range idPattern.FindAllStringSubmatch(match[1], -1) {
+			id, err := strconv.ParseInt(ref[1], 10, 64)
+			if err != nil || completed[id] {
+				continue
+			}
+			completed[id] = true
+			actions = append(actions, Action{Kind: ActionComplete, TaskID: id})
+		}
+	}
+
+	for _, match := range progressPattern.FindAllStringSubmatch(message, -1) {
+		id, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || completed[id] {
+			continue
+		}
+		progress, _ := strconv.Atoi(match[2])
+		actions = append(actions, Action{Kind: ActionProgress, TaskID: id, Progress: progress})
+	}
+
+	return actions
+}
+
+// StripComments removes the lines git treats as comments from a commit message file
+func StripComments(message string) string {
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,176 @@
+// Package hooks runs the executable scripts a workspace keeps for task
+// lifecycle events
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Timeout bounds how long a single script may run
+const Timeout = 30 * time.Second
+
+// Runner runs the scripts in a hooks directory. A script belongs to a hook
+// when its name is the hook name, optionally followed by a suffix starting
+// with '.', '-' or '_' (pre-add, pre-add.sh, pre-add-10-policy); scripts of
+// one hook run in name order.
+type Runner struct {
+	dir string
+	// Dir is the working directory of the scripts
+	Dir string
+	// Env is added to the environment of the scripts
+	Env []string
+	// Output receives what notification scripts print
+	Output io.Writer
+}
+
+// Error reports a script that failed or rejected its input
+type Error struct {
+	Script string
+	Err    error
+	// Message is what the script printed on stderr
+	Message string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s: %s", e.Script, e.Message)
+	}
+	return fmt.Sprintf("%s: %v", e.Script, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NewRunner returns a runner for the scripts in dir
+func NewRunner(dir string) *Runner {
+	return &Runner{dir: dir, Output: os.Stderr}
+}
+
+// Scripts returns the paths of the executable scripts for a hook, in the
+// order they run
+func (r *Runner) Scripts(hook string) ([]string, error) {
+	entries, err := os.ReadDir(r.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read hooks directory: %w", err)
+	}
+
+	var scripts []string
+	for _, entry := range entries {
+		if !matches(entry.Name(), hook) || entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !Executable(info) {
+			continue
+		}
+		scripts = append(scripts, filepath.Join(r.dir, entry.Name()))
+	}
+
+	sort.Strings(scripts)
+	return scripts, nil
+}
+
+// Filter passes input through the scripts of a hook in turn. A script that
+// prints something replaces the input of the next one; the result is nil when
+// no script printed anything. The first script to exit non-zero stops the
+// chain with an *Error.
+func (r *Runner) Filter(hook string, input []byte) ([]byte, error) {
+	scripts, err := r.Scripts(hook)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []byte
+	for _, script := range scripts {
+		var stdout, stderr bytes.Buffer
+		if err := r.run(script, hook, input, &stdout, &stderr); err != nil {
+			return nil, &Error{Script: filepath.Base(script), Err: err, Message: strings.TrimSpace(stderr.String())}
+		}
+		if out := bytes.TrimSpace(stdout.Bytes()); len(out) > 0 {
+			input = out
+			result = out
+		}
+	}
+	return result, nil
+}
+
+// Notify runs every script of a hook with the same input, forwarding what
+// they print to Output. Failures do not stop the remaining scripts.
+func (r *Runner) Notify(hook string, input []byte) error {
+	scripts, err := r.Scripts(hook)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, script := range scripts {
+		if err := r.run(script, hook, input, r.Output, r.Output); err != nil {
+			errs = append(errs, &Error{Script: filepath.Base(script), Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run executes one script with input on stdin
+func (r *Runner) run(script, hook string, input []byte, stdout, stderr io.Writer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, script)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), r.Env...)
+	cmd.Env = append(cmd.Env, "TASKMASTER_HOOK="+hook)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("timed out after %s", Timeout)
+	}
+	return err
+}
+
+// matches reports whether a file name belongs to a hook. Editor backups and
+// scripts renamed to .sample or .disabled are left out.
+func matches(name, hook string) bool {
+	if !strings.HasPrefix(name, hook) || strings.HasSuffix(name, "~") ||
+		strings.HasSuffix(name, ".sample") || strings.HasSuffix(name, ".disabled") {
+		return false
+	}
+	rest := name[len(hook):]
+	return rest == "" || strings.ContainsAny(rest[:1], ".-_")
+}
+
+// Executable reports whether a file can be run as a script: on Windows by its
+// extension, elsewhere by its permission bits
+func Executable(info os.FileInfo) bool {
+	if !info.Mode().IsRegular() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		switch strings.ToLower(filepath.Ext(info.Name())) {
+		case ".exe", ".bat", ".cmd", ".com":
+			return true
+		}
+		return false
+	}
+	return info.Mode().Perm()&0111 != 0
+}
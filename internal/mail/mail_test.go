@@ -0,0 +1,194 @@
+package mail
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readParts parses a message and returns its headers and the decoded body
+// of each part, keyed by media type, with line endings turned back into \n
+func readParts(t *testing.T, data []byte) (*netmail.Message, map[string]string) {
+	t.Helper()
+
+	msg, err := netmail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("ReadMessage() error: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextRawPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextRawPart() error: %v", err)
+		}
+		if enc := part.Header.Get("Content-Transfer-Encoding"); enc != "quoted-printable" {
+			t.Errorf("Content-Transfer-Encoding = %q, want quoted-printable", enc)
+		}
+		body, err := io.ReadAll(quotedprintable.NewReader(part))
+		if err != nil {
+			t.Fatalf("decoding part: %v", err)
+		}
+		partType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		parts[partType] = strings.ReplaceAll(string(body), "\r\n", "\n")
+	}
+	return msg, parts
+}
+
+func TestMessageBytes(t *testing.T) {
+	m := &Message{
+		From:    "TaskMaster <tm@example.com>",
+		To:      []string{"a@example.com", "Bé <b@example.com>"},
+		Subject: "[api] 2 overdue — résumé",
+		Date:    time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC),
+		Text:    "Overdue:\n  #1 Fix the build\n" + strings.Repeat("long line ", 20) + "\n",
+		HTML:    "<p>Overdue: <b>Fix the build</b> &amp; more</p>",
+	}
+
+	data, err := m.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error: %v", err)
+	}
+	msg, parts := readParts(t, data)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil || subject != m.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, m.Subject)
+	}
+	to, err := msg.Header.AddressList("To")
+	if err != nil || len(to) != 2 || to[1].Address != "b@example.com" || to[1].Name != "Bé" {
+		t.Errorf("To = %v (%v), want both recipients", to, err)
+	}
+	if date, err := msg.Header.Date(); err != nil || !date.Equal(m.Date) {
+		t.Errorf("Date = %v (%v), want %v", date, err, m.Date)
+	}
+	if id := msg.Header.Get("Message-ID"); !strings.HasSuffix(id, "@example.com>") {
+		t.Errorf("Message-ID = %q, want one in the sender's domain", id)
+	}
+
+	if parts["text/plain"] != m.Text {
+		t.Errorf("text part = %q, want %q", parts["text/plain"], m.Text)
+	}
+	if parts["text/html"] != m.HTML {
+		t.Errorf("HTML part = %q, want %q", parts["text/html"], m.HTML)
+	}
+
+	for i, line := range strings.Split(string(data), "\r\n") {
+		if len(line) > 998 {
+			t.Errorf("line %d is %d characters long", i+1, len(line))
+		}
+	}
+}
+
+func TestMessageBytesRejectsInvalidAddresses(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Message
+	}{
+		{"bad sender", Message{From: "not an address", To: []string{"a@example.com"}}},
+		{"bad recipient", Message{From: "tm@example.com", To: []string{"a@"}}},
+		{"no recipients", Message{From: "tm@example.com"}},
+	}
+	for _, tt := range tests {
+		if _, err := tt.m.Bytes(); err == nil {
+			t.Errorf("%s: Bytes() succeeded, want an error", tt.name)
+		}
+	}
+}
+
+// received is a message accepted by the stand-in server
+type received struct {
+	from string
+	to   []string
+	data []byte
+}
+
+// startReceiver runs the stand-in server on a free local port
+func startReceiver(t *testing.T) (Server, <-chan received) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan received, 1)
+	receiver := &Receiver{Handle: func(from string, to []string, data []byte) {
+		messages <- received{from, to, data}
+	}}
+	go receiver.Serve(listener)
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	return Server{Host: "127.0.0.1", Port: port, TLS: TLSNone}, messages
+}
+
+func TestSendToReceiver(t *testing.T) {
+	server, messages := startReceiver(t)
+	server.Username = "tm@example.com"
+	server.Password = "secret"
+
+	m := &Message{
+		From:    "TaskMaster <tm@example.com>",
+		To:      []string{"a@example.com", "b@example.com"},
+		Subject: "Weekly digest",
+		Date:    time.Now(),
+		Text:    "Nothing overdue.\n.\nA line with only a dot above.\n",
+		HTML:    "<p>Nothing overdue.</p>",
+	}
+	if err := Send(server, m); err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+
+	select {
+	case got := <-messages:
+		if got.from != "tm@example.com" {
+			t.Errorf("MAIL FROM = %q, want tm@example.com", got.from)
+		}
+		if strings.Join(got.to, ",") != "a@example.com,b@example.com" {
+			t.Errorf("RCPT TO = %v, want both recipients", got.to)
+		}
+		msg, parts := readParts(t, got.data)
+		if msg.Header.Get("Subject") != m.Subject {
+			t.Errorf("Subject = %q, want %q", msg.Header.Get("Subject"), m.Subject)
+		}
+		if parts["text/plain"] != m.Text {
+			t.Errorf("text part = %q, want %q", parts["text/plain"], m.Text)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+	}
+}
+
+func TestSendRequiresStartTLS(t *testing.T) {
+	server, _ := startReceiver(t)
+	server.TLS = TLSStartTLS
+
+	m := &Message{From: "tm@example.com", To: []string{"a@example.com"}, Subject: "x", Date: time.Now()}
+	err := Send(server, m)
+	if err == nil || !strings.Contains(err.Error(), "STARTTLS") {
+		t.Errorf("Send() error = %v, want one about STARTTLS", err)
+	}
+}
+
+func TestSendRejectsUnknownTLSMode(t *testing.T) {
+	m := &Message{From: "tm@example.com", To: []string{"a@example.com"}, Date: time.Now()}
+	if err := Send(Server{Host: "127.0.0.1", Port: 1, TLS: "ssl3"}, m); err == nil {
+		t.Error("Send() succeeded with an unknown TLS mode")
+	}
+}
@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Receiver is a minimal SMTP server that accepts every message, for trying
+// out mail without a real server. It offers no TLS and accepts any login.
+type Receiver struct {
+	// Handle is called with each message received
+	Handle func(from string, to []string, data []byte)
+}
+
+// Serve accepts connections on l until it is closed
+func (r *Receiver) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go r.serveConn(conn)
+	}
+}
+
+// serveConn runs one SMTP session
+func (r *Receiver) serveConn(conn net.Conn) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Minute))
+
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost TaskMaster SMTP stand-in")
+
+	var from string
+	var to []string
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			tp.PrintfLine("250-localhost")
+			tp.PrintfLine("250-8BITMIME")
+			tp.PrintfLine("250 AUTH PLAIN LOGIN")
+		case "HELO":
+			tp.PrintfLine("250 localhost")
+		case "AUTH":
+			mech, initial, _ := strings.Cut(arg, " ")
+			switch {
+			case strings.EqualFold(mech, "PLAIN") && initial == "":
+				tp.PrintfLine("334 ")
+				tp.ReadLine()
+			case strings.EqualFold(mech, "LOGIN"):
+				tp.PrintfLine("334 VXNlcm5hbWU6")
+				tp.ReadLine()
+				tp.PrintfLine("334 UGFzc3dvcmQ6")
+				tp.ReadLine()
+			}
+			tp.PrintfLine("235 Authentication succeeded")
+		case "MAIL":
+			from, to = bracketed(arg), nil
+			tp.PrintfLine("250 OK")
+		case "RCPT":
+			to = append(to, bracketed(arg))
+			tp.PrintfLine("250 OK")
+		case "DATA":
+			tp.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			tp.PrintfLine("250 OK")
+			if r.Handle != nil {
+				r.Handle(from, to, data)
+			}
+		case "RSET":
+			from, to = "", nil
+			tp.PrintfLine("250 OK")
+		case "NOOP":
+			tp.PrintfLine("250 OK")
+		case "QUIT":
+			tp.PrintfLine("221 Bye")
+			return
+		default:
+			tp.PrintfLine("502 Command not implemented")
+		}
+	}
+}
+
+// bracketed returns the address inside the angle brackets of a MAIL or RCPT
+// argument such as "FROM:<a@example.com> BODY=8BITMIME"
+func bracketed(arg string) string {
+	start := strings.Index(arg, "<")
+	end := strings.Index(arg, ">")
+	if start < 0 || end < start {
+		return arg
+	}
+	return arg[start+1 : end]
+}
@@ -0,0 +1,172 @@
+// Package metrics records counters and histograms and writes them in the
+// Prometheus text exposition format
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the media type of the text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultBuckets are histogram bounds in seconds suited to local file access
+var DefaultBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1}
+
+// Counter counts events, keyed by the value of a single label
+type Counter struct {
+	mu     sync.Mutex
+	values map[string]uint64
+}
+
+// Inc adds one to the count for a label value
+func (c *Counter) Inc(label string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.values == nil {
+		c.values = make(map[string]uint64)
+	}
+	c.values[label]++
+}
+
+// Write writes the counter with one sample per label value; values listed in
+// known are written even before they are first counted
+func (c *Counter) Write(w io.Writer, name, help, labelName string, known ...string) {
+	c.mu.Lock()
+	values := make(map[string]uint64, len(c.values))
+	for label, v := range c.values {
+		values[label] = v
+	}
+	c.mu.Unlock()
+
+	for _, label := range known {
+		if _, ok := values[label]; !ok {
+			values[label] = 0
+		}
+	}
+
+	Header(w, name, help, "counter")
+	for _, label := range sortedKeys(values) {
+		Sample(w, name, float64(values[label]), labelName, label)
+	}
+}
+
+// Histogram counts observations into buckets, keyed by the value of a single
+// label
+type Histogram struct {
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+// histogramSeries holds the observations for one label value
+type histogramSeries struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+// NewHistogram returns a histogram with the given upper bucket bounds
+func NewHistogram(buckets []float64) *Histogram {
+	sorted := append([]float64(nil), buckets...)
+	sort.Float64s(sorted)
+	return &Histogram{buckets: sorted, series: make(map[string]*histogramSeries)}
+}
+
+// Observe records a value for a label value
+func (h *Histogram) Observe(label string, v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	s, ok := h.series[label]
+	if !ok {
+		s = &histogramSeries{counts: make([]uint64, len(h.buckets))}
+		h.series[label] = s
+	}
+	if i := sort.SearchFloat64s(h.buckets, v); i < len(h.buckets) {
+		s.counts[i]++
+	}
+	s.count++
+	s.sum += v
+}
+
+// Write writes the cumulative buckets, sum and count of every label value
+func (h *Histogram) Write(w io.Writer, name, help, labelName string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	Header(w, name, help, "histogram")
+	for _, label := range sortedKeys(h.series) {
+		s := h.series[label]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += s.counts[i]
+			Sample(w, name+"_bucket", float64(cumulative), labelName, label, "le", formatValue(bound))
+		}
+		Sample(w, name+"_bucket", float64(s.count), labelName, label, "le", "+Inf")
+		Sample(w, name+"_sum", s.sum, labelName, label)
+		Sample(w, name+"_count", float64(s.count), labelName, label)
+	}
+}
+
+// Header writes the HELP and TYPE lines of a metric
+func Header(w io.Writer, name, help, kind string) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+}
+
+// Sample writes one sample; labels are given as name, value pairs
+func Sample(w io.Writer, name string, value float64, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labels[i])
+			b.WriteString(`="`)
+			b.WriteString(labelEscaper.Replace(labels[i+1]))
+			b.WriteByte('"')
+		}
+		b.WriteByte('}')
+	}
+	b.WriteByte(' ')
+	b.WriteString(formatValue(value))
+	b.WriteByte('\n')
+	io.WriteString(w, b.String())
+}
+
+// labelEscaper escapes label values
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// formatValue formats a sample value or bucket bound
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// sortedKeys returns the keys of a map in order, so output is stable
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gookit/color"
@@ -51,15 +52,86 @@ func (p Priority) ColoredString() string {
 
 // Task represents a task in the task manager
 type Task struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
+	ID          int64        `json:"id"`
+	UUID        string       `json:"uuid,omitempty"`
+	ExternalID  string       `json:"external_id,omitempty"` // ID in the tool it was imported from, when not a UUID
+	Title       string       `json:"title"`
+	Description string       `json:"description"`
+	DueDate     time.Time    `json:"due_date"`
+	Priority    Priority     `json:"priority"`
+	Completed   bool         `json:"completed"`
+	Progress    int          `json:"progress"` // 0-100 percentage
+	Tags        []string     `json:"tags,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"` // Set only while completed
+	Annotations []Annotation `json:"annotations,omitempty"`
+	Source      *SourceRef   `json:"source,omitempty"`
+}
+
+// SourceRef links a task to a TODO-style comment in the source code
+type SourceRef struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+// Location returns the file:line reference of the comment
+func (s SourceRef) Location() string {
+	return fmt.Sprintf("%s:%d", s.File, s.Line)
+}
+
+// Annotation is a timestamped note attached to a task
+type Annotation struct {
+	Entry       time.Time `json:"entry"`
 	Description string    `json:"description"`
-	DueDate     time.Time `json:"due_date"`
-	Priority    Priority  `json:"priority"`
-	Completed   bool      `json:"completed"`
-	Progress    int       `json:"progress"` // 0-100 percentage
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// SetImportedID records the identifier a task has in another tool: as its
+// UUID when it is one, so both tools share it, and as its external ID otherwise
+func (t *Task) SetImportedID(id string) {
+	id = strings.TrimSpace(id)
+	if ValidUUID(id) {
+		t.UUID = strings.ToLower(id)
+		return
+	}
+	t.ExternalID = id
+}
+
+// CompletionTime returns when the task was completed, or the zero time if it
+// is open or was completed before completion times were recorded
+func (t Task) CompletionTime() time.Time {
+	if t.CompletedAt == nil {
+		return time.Time{}
+	}
+	return *t.CompletedAt
+}
+
+// MarkCompleted marks the task as completed, keeping the original completion
+// time if it was already completed
+func (t *Task) MarkCompleted(now time.Time) {
+	if !t.Completed || t.CompletionTime().IsZero() {
+		t.CompletedAt = &now
+	}
+	t.Completed = true
+	t.Progress = 100
+}
+
+// MarkOpen clears the completion state of the task
+func (t *Task) MarkOpen() {
+	t.Completed = false
+	t.CompletedAt = nil
+}
+
+// HasTag reports whether the task carries the given tag (case-insensitive)
+func (t Task) HasTag(tag string) bool {
+	for _, existing := range t.Tags {
+		if strings.EqualFold(existing, tag) {
+			return true
+		}
+	}
+	return false
 }
 
 // FormatDueDate returns a formatted string of the due date
@@ -0,0 +1,122 @@
+package remind
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// commandTimeout bounds how long a notification command may run
+const commandTimeout = 30 * time.Second
+
+// Message is a reminder ready to be delivered
+type Message struct {
+	Title string
+	Body  string
+	// Urgent asks for a notification that stays until dismissed
+	Urgent bool
+	// Env describes the reminder to notification commands
+	Env []string
+	// Input is given to notification commands on stdin
+	Input []byte
+}
+
+// Notifier delivers reminders
+type Notifier interface {
+	Notify(m Message) error
+}
+
+// Desktop shows reminders as desktop notifications with notify-send, or
+// with osascript on macOS
+type Desktop struct{}
+
+// Available reports whether desktop notifications can be shown
+func (Desktop) Available() error {
+	tool := "notify-send"
+	if runtime.GOOS == "darwin" {
+		tool = "osascript"
+	}
+	if _, err := exec.LookPath(tool); err != nil {
+		return fmt.Errorf("%s not found", tool)
+	}
+	return nil
+}
+
+// Notify implements Notifier
+func (Desktop) Notify(m Message) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "darwin" {
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(m.Body), strconv.Quote(m.Title))
+		cmd = exec.Command("osascript", "-e", script)
+	} else {
+		urgency := "normal"
+		if m.Urgent {
+			urgency = "critical"
+		}
+		cmd = exec.Command("notify-send", "--app-name=TaskMaster", "--urgency="+urgency, m.Title, m.Body)
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// Bell rings the terminal bell
+type Bell struct {
+	Out io.Writer
+}
+
+// Notify implements Notifier
+func (b Bell) Notify(Message) error {
+	_, err := io.WriteString(b.Out, "\a")
+	return err
+}
+
+// Command runs a shell command for each reminder, with the reminder in the
+// environment and the task as JSON on stdin
+type Command struct {
+	Line string
+	// Dir is the working directory of the command
+	Dir string
+}
+
+// Notify implements Notifier
+func (c Command) Notify(m Message) error {
+	if strings.TrimSpace(c.Line) == "" {
+		return errors.New("no reminder command configured")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", c.Line)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", c.Line)
+	}
+	cmd.Dir = c.Dir
+	cmd.Env = append(os.Environ(), "TASKMASTER_REMINDER_TITLE="+m.Title, "TASKMASTER_REMINDER_BODY="+m.Body)
+	cmd.Env = append(cmd.Env, m.Env...)
+	cmd.Stdin = bytes.NewReader(m.Input)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("%w: %s", err, msg)
+		}
+		return err
+	}
+	return nil
+}
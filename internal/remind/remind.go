@@ -0,0 +1,186 @@
+// Package remind keeps the reminder state of a workspace and delivers
+// reminders as desktop notifications, terminal bells or commands
+package remind
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// stateFile is the name of the state file inside the state directory
+const stateFile = "state.json"
+
+// Scheduled is a reminder requested for a given time
+type Scheduled struct {
+	UUID string    `json:"uuid"`
+	At   time.Time `json:"at"`
+}
+
+// State is what is remembered between runs: the reminders requested for a
+// given time and the reminders already sent
+type State struct {
+	dir       string
+	Scheduled []Scheduled          `json:"scheduled"`
+	Sent      map[string]time.Time `json:"sent"`
+}
+
+// Load reads the state kept in dir, returning an empty state if there is none
+func Load(dir string) (*State, error) {
+	s := &State{dir: dir, Sent: map[string]time.Time{}}
+
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read reminder state: %w", err)
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse reminder state: %w", err)
+	}
+	if s.Sent == nil {
+		s.Sent = map[string]time.Time{}
+	}
+	return s, nil
+}
+
+// Save writes the state, creating its directory on first use. The directory
+// is personal to this checkout and kept out of version control.
+func (s *State) Save() error {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create reminder directory: %w", err)
+	}
+	ignore := filepath.Join(s.dir, ".gitignore")
+	if _, err := os.Stat(ignore); os.IsNotExist(err) {
+		os.WriteFile(ignore, []byte("*\n"), 0644)
+	}
+
+	sort.Slice(s.Scheduled, func(i, j int) bool { return s.Scheduled[i].At.Before(s.Scheduled[j].At) })
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal reminder state: %w", err)
+	}
+
+	path := filepath.Join(s.dir, stateFile)
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		return fmt.Errorf("failed to write reminder state: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to write reminder state: %w", err)
+	}
+	return nil
+}
+
+// Schedule adds a reminder about a task at the given time
+func (s *State) Schedule(uuid string, at time.Time) {
+	s.Scheduled = append(s.Scheduled, Scheduled{UUID: uuid, At: at})
+}
+
+// Cancel removes the reminders scheduled for a task, returning how many
+// there were
+func (s *State) Cancel(uuid string) int {
+	kept := s.Scheduled[:0]
+	for _, r := range s.Scheduled {
+		if r.UUID != uuid {
+			kept = append(kept, r)
+		}
+	}
+	removed := len(s.Scheduled) - len(kept)
+	s.Scheduled = kept
+	return removed
+}
+
+// ParseDuration parses a duration such as "90m", "4h", "2d" or "1w". Days and
+// weeks may be combined with the units time.ParseDuration accepts ("1d12h").
+func ParseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "0" {
+		return 0, nil
+	}
+	if value == "" {
+		return 0, errors.New("empty duration")
+	}
+
+	var total time.Duration
+	rest := value
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		i := strings.Index(rest, unit.suffix)
+		if i < 0 {
+			continue
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += time.Duration(n) * unit.size
+		rest = rest[i+1:]
+	}
+
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += d
+	}
+	return total, nil
+}
+
+// ParseWhen parses the time of a reminder relative to now: "in 2h", "in 3d",
+// "tomorrow", "tomorrow 9:00", "17:30" (today, or tomorrow once it has
+// passed), "2025-03-01" (9:00 that day) or "2025-03-01 14:00", in local time
+func ParseWhen(value string, now time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if rest, ok := strings.CutPrefix(value, "in "); ok {
+		d, err := ParseDuration(strings.ReplaceAll(rest, " ", ""))
+		if err != nil {
+			return time.Time{}, err
+		}
+		return now.Add(d), nil
+	}
+
+	if rest, ok := strings.CutPrefix(value, "tomorrow"); ok {
+		day := now.AddDate(0, 0, 1)
+		clock := strings.TrimSpace(rest)
+		if clock == "" {
+			clock = "9:00"
+		}
+		return atClock(day, clock, value)
+	}
+
+	if t, err := time.ParseInLocation("2006-01-02 15:04", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t.Add(9 * time.Hour), nil
+	}
+
+	t, err := atClock(now, value, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !t.After(now) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// atClock returns the given clock time on the day of t
+func atClock(t time.Time, clock, value string) (time.Time, error) {
+	c, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, errors.New(`invalid time "` + value + `": use "in 2h", "tomorrow 9:00", "17:30" or "YYYY-MM-DD HH:MM"`)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), c.Hour(), c.Minute(), 0, 0, t.Location()), nil
+}
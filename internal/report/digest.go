@@ -0,0 +1,111 @@
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"taskmaster/internal/models"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/digest/*.tmpl
+var digestFiles embed.FS
+
+// Digest holds the contents of an email digest
+type Digest struct {
+	Title       string
+	Workspace   string
+	GeneratedAt time.Time
+	Since       time.Time
+	Until       time.Time
+	Overdue     []Task
+	DueSoon     []Task
+	Completed   []Task
+}
+
+// BuildDigest collects the overdue tasks, the open tasks due within the next
+// dueSoonDays days and the tasks completed within the last days days. Each
+// list is ordered by date, most pressing first.
+func BuildDigest(title, workspace string, tasks []*models.Task, now time.Time, days, dueSoonDays int) *Digest {
+	d := &Digest{
+		Title:       title,
+		Workspace:   workspace,
+		GeneratedAt: now,
+		Since:       now.AddDate(0, 0, -days),
+		Until:       now.AddDate(0, 0, dueSoonDays),
+	}
+
+	var overdue, dueSoon, completed []*models.Task
+	for _, task := range tasks {
+		switch {
+		case task.Completed:
+			doneAt := task.CompletionTime()
+			if doneAt.IsZero() {
+				doneAt = task.UpdatedAt
+			}
+			if !doneAt.Before(d.Since) {
+				completed = append(completed, task)
+			}
+		case task.DueDate.IsZero():
+		case now.After(task.DueDate):
+			overdue = append(overdue, task)
+		case task.DueDate.Before(d.Until):
+			dueSoon = append(dueSoon, task)
+		}
+	}
+
+	byDue := func(list []*models.Task) {
+		sort.SliceStable(list, func(i, j int) bool {
+			if !list[i].DueDate.Equal(list[j].DueDate) {
+				return list[i].DueDate.Before(list[j].DueDate)
+			}
+			return list[i].Priority > list[j].Priority
+		})
+	}
+	byDue(overdue)
+	byDue(dueSoon)
+	sort.SliceStable(completed, func(i, j int) bool {
+		return completed[i].CompletionTime().After(completed[j].CompletionTime())
+	})
+
+	for _, task := range overdue {
+		d.Overdue = append(d.Overdue, newTask(task, now))
+	}
+	for _, task := range dueSoon {
+		d.DueSoon = append(d.DueSoon, newTask(task, now))
+	}
+	for _, task := range completed {
+		d.Completed = append(d.Completed, newTask(task, now))
+	}
+	return d
+}
+
+// Empty reports whether the digest has nothing to show
+func (d *Digest) Empty() bool {
+	return len(d.Overdue) == 0 && len(d.DueSoon) == 0 && len(d.Completed) == 0
+}
+
+// RenderDigest writes the plain text ("text") or HTML ("html") body of a digest
+func RenderDigest(w io.Writer, d *Digest, format string) error {
+	source, err := digestFiles.ReadFile("templates/digest/" + format + ".tmpl")
+	if err != nil {
+		return fmt.Errorf("unsupported digest format: %s", format)
+	}
+
+	if format == "html" {
+		tmpl, err := htmltemplate.New("digest").Funcs(templateFuncs).Parse(string(source))
+		if err != nil {
+			return fmt.Errorf("failed to parse digest template: %w", err)
+		}
+		return tmpl.Execute(w, d)
+	}
+
+	tmpl, err := texttemplate.New("digest").Funcs(templateFuncs).Parse(string(source))
+	if err != nil {
+		return fmt.Errorf("failed to parse digest template: %w", err)
+	}
+	return tmpl.Execute(w, d)
+}
@@ -0,0 +1,200 @@
+// Package report builds status reports of a workspace and renders them with templates
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"sort"
+	"strings"
+	"taskmaster/internal/models"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// Formats lists the supported report formats
+var Formats = []string{"markdown", "html"}
+
+// Report holds everything a report template can display
+type Report struct {
+	Title        string
+	GeneratedAt  time.Time
+	Since        time.Time
+	Days         int
+	Open         []PriorityGroup
+	Overdue      []PriorityGroup
+	Completed    []PriorityGroup
+	OpenCount    int
+	OverdueCount int
+	DoneCount    int
+}
+
+// PriorityGroup is a list of tasks sharing a priority
+type PriorityGroup struct {
+	Priority string
+	Tasks    []Task
+}
+
+// Task is a task prepared for display in a report
+type Task struct {
+	*models.Task
+	PriorityName string
+	Due          string
+	DaysOverdue  int
+	Completed    string
+}
+
+// Build groups tasks into open, overdue and recently completed sections.
+// Tasks completed within the last days days count as recently completed.
+func Build(title string, tasks []*models.Task, now time.Time, days int) *Report {
+	r := &Report{
+		Title:       title,
+		GeneratedAt: now,
+		Since:       now.AddDate(0, 0, -days),
+		Days:        days,
+	}
+
+	var open, overdue, completed []*models.Task
+	for _, task := range tasks {
+		switch {
+		case task.Completed:
+			doneAt := task.CompletionTime()
+			if doneAt.IsZero() {
+				doneAt = task.UpdatedAt
+			}
+			if !doneAt.Before(r.Since) {
+				completed = append(completed, task)
+			}
+		case !task.DueDate.IsZero() && now.After(task.DueDate):
+			overdue = append(overdue, task)
+		default:
+			open = append(open, task)
+		}
+	}
+
+	r.OpenCount, r.OverdueCount, r.DoneCount = len(open), len(overdue), len(completed)
+	r.Open = groupByPriority(open, now)
+	r.Overdue = groupByPriority(overdue, now)
+	r.Completed = groupByPriority(completed, now)
+
+	return r
+}
+
+// groupByPriority groups tasks by priority, most important first, ordering
+// each group by due date
+func groupByPriority(tasks []*models.Task, now time.Time) []PriorityGroup {
+	var groups []PriorityGroup
+	for p := models.Critical; p >= models.Low; p-- {
+		var group []*models.Task
+		for _, task := range tasks {
+			if task.Priority == p {
+				group = append(group, task)
+			}
+		}
+		if len(group) == 0 {
+			continue
+		}
+
+		sort.SliceStable(group, func(i, j int) bool {
+			a, b := group[i].DueDate, group[j].DueDate
+			if a.IsZero() != b.IsZero() {
+				return !a.IsZero()
+			}
+			return a.Before(b)
+		})
+
+		pg := PriorityGroup{Priority: p.String()}
+		for _, task := range group {
+			pg.Tasks = append(pg.Tasks, newTask(task, now))
+		}
+		groups = append(groups, pg)
+	}
+	return groups
+}
+
+// newTask prepares a task for display
+func newTask(task *models.Task, now time.Time) Task {
+	t := Task{Task: task, PriorityName: task.Priority.String(), Due: "—"}
+	if !task.DueDate.IsZero() {
+		t.Due = task.DueDate.Format("2006-01-02")
+		if !task.Completed && now.After(task.DueDate) {
+			t.DaysOverdue = int(now.Sub(task.DueDate).Hours() / 24)
+		}
+	}
+	if task.Completed {
+		doneAt := task.CompletionTime()
+		if doneAt.IsZero() {
+			doneAt = task.UpdatedAt
+		}
+		t.Completed = doneAt.Format("2006-01-02")
+	}
+	return t
+}
+
+// templateFuncs are available to both built-in and custom templates
+var templateFuncs = map[string]any{
+	"bar": progressBar,
+	"date": func(t time.Time) string {
+		return t.Format("2006-01-02")
+	},
+	"datetime": func(t time.Time) string {
+		return t.Format("2006-01-02 15:04")
+	},
+	"join":           strings.Join,
+	"lower":          strings.ToLower,
+	"escapeMarkdown": escapeMarkdown,
+}
+
+// progressBar renders a text progress bar ten characters wide
+func progressBar(progress int) string {
+	// Imported and hand-edited tasks may hold progress outside 0-100
+	filled := min(max(progress, 0), 100) / 10
+	return strings.Repeat("█", filled) + strings.Repeat("░", 10-filled)
+}
+
+// escapeMarkdown escapes characters that would break a Markdown table cell
+func escapeMarkdown(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ", "*", `\*`, "_", `\_`).Replace(s)
+}
+
+// DefaultTemplate returns the built-in template source for a format
+func DefaultTemplate(format string) (string, error) {
+	data, err := templateFiles.ReadFile("templates/" + format + ".tmpl")
+	if err != nil {
+		return "", fmt.Errorf("unsupported report format: %s", format)
+	}
+	return string(data), nil
+}
+
+// Render writes the report in the given format. If source is empty the
+// built-in template for the format is used. HTML templates escape their
+// output automatically.
+func Render(w io.Writer, r *Report, format, source string) error {
+	if source == "" {
+		var err error
+		if source, err = DefaultTemplate(format); err != nil {
+			return err
+		}
+	}
+
+	switch format {
+	case "html":
+		tmpl, err := htmltemplate.New("report").Funcs(templateFuncs).Parse(source)
+		if err != nil {
+			return fmt.Errorf("failed to parse report template: %w", err)
+		}
+		return tmpl.Execute(w, r)
+	case "markdown":
+		tmpl, err := texttemplate.New("report").Funcs(templateFuncs).Parse(source)
+		if err != nil {
+			return fmt.Errorf("failed to parse report template: %w", err)
+		}
+		return tmpl.Execute(w, r)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
@@ -0,0 +1,21 @@
+package report
+
+import "testing"
+
+func TestProgressBar(t *testing.T) {
+	tests := []struct {
+		progress int
+		want     string
+	}{
+		{0, "░░░░░░░░░░"},
+		{45, "████░░░░░░"},
+		{100, "██████████"},
+		{150, "██████████"},
+		{-20, "░░░░░░░░░░"},
+	}
+	for _, tt := range tests {
+		if got := progressBar(tt.progress); got != tt.want {
+			t.Errorf("progressBar(%d) = %q, want %q", tt.progress, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// Package scan finds TODO, FIXME and HACK comments in source code
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Comment is a TODO-style comment found in a source file
+type Comment struct {
+	File string // Path relative to the scan root, using forward slashes
+	Line int
+	Kind string // TODO, FIXME or HACK
+	Text string
+}
+
+// maxFileSize is the largest file that will be scanned
+const maxFileSize = 1 << 20
+
+// skippedDirs are never scanned regardless of .gitignore
+var skippedDirs = map[string]bool{
+	".git":        true,
+	".hg":         true,
+	".svn":        true,
+	".taskmaster": true,
+}
+
+// commentMarkers maps file extensions to the markers that start a comment
+var commentMarkers = map[string][]string{}
+
+func init() {
+	register := func(markers []string, extensions ...string) {
+		for _, ext := range extensions {
+			commentMarkers[ext] = markers
+		}
+	}
+
+	cStyle := []string{"//", "/*", "*"}
+	register(cStyle, ".go", ".c", ".h", ".cc", ".cpp", ".hpp", ".cs", ".java", ".kt", ".kts", ".scala",
+		".swift", ".rs", ".js", ".jsx", ".mjs", ".ts", ".tsx", ".dart", ".css", ".scss", ".less", ".proto", ".zig")
+	register([]string{"//", "/*", "*", "#"}, ".php")
+	register([]string{"#"}, ".py", ".rb", ".sh", ".bash", ".zsh", ".fish", ".pl", ".pm", ".r", ".yaml", ".yml",
+		".toml", ".tf", ".cmake", ".ps1", ".nim", ".ex", ".exs", ".cfg", ".conf", ".ini", ".mk")
+	register([]string{"--"}, ".sql", ".lua", ".hs", ".elm")
+	register([]string{";"}, ".lisp", ".clj", ".cljs", ".el", ".scm", ".asm")
+	register([]string{"%"}, ".tex", ".erl", ".m")
+	register([]string{"<!--"}, ".html", ".htm", ".xml", ".md", ".vue", ".svelte")
+	register([]string{"{{/*", "//", "/*", "*"}, ".tmpl", ".gotmpl")
+}
+
+// markersForFile returns the comment markers of a file, or nil if its language is unknown
+func markersForFile(name string) []string {
+	switch filepath.Base(name) {
+	case "Makefile", "makefile", "GNUmakefile", "Dockerfile", "Gemfile", "Rakefile", "Vagrantfile":
+		return []string{"#"}
+	}
+	return commentMarkers[strings.ToLower(filepath.Ext(name))]
+}
+
+// tagPattern matches the tag and text following a comment marker, allowing an
+// optional author such as TODO(alice):
+var tagPattern = regexp.MustCompile(`^\s*(TODO|FIXME|HACK)\b(?:\([^)]*\))?\s*:?\s*(.*)$`)
+
+// closingMarkers end the comment text on the line
+var closingMarkers = []string{"*/", "-->", "}}"}
+
+// Scan walks the given paths below root, honouring .gitignore files, and returns
+// every TODO, FIXME and HACK comment found in source files of known languages
+func Scan(root string, paths []string) ([]Comment, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		paths = []string{root}
+	}
+
+	var comments []Comment
+	for _, p := range paths {
+		start, err := filepath.Abs(p)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(start); err != nil {
+			return nil, fmt.Errorf("cannot scan %s: %w", p, err)
+		}
+
+		found, err := scanTree(root, start)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, found...)
+	}
+
+	return comments, nil
+}
+
+// RelPath returns a path relative to root with forward slashes, as stored in Comment.File
+func RelPath(root, p string) string {
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return filepath.ToSlash(p)
+	}
+	rootAbs, err := filepath.Abs(root)
+	if err != nil {
+		return filepath.ToSlash(abs)
+	}
+	rel, err := filepath.Rel(rootAbs, abs)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(abs)
+	}
+	if rel == "." {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// scanTree scans a single file or directory tree
+func scanTree(root, start string) ([]Comment, error) {
+	matcher := &ignoreMatcher{}
+
+	// Rules from .gitignore files above the starting point also apply
+	if rel := RelPath(root, start); rel != "" && !filepath.IsAbs(rel) {
+		matcher.load(root, "")
+		dir := ""
+		parts := strings.Split(rel, "/")
+		for _, part := range parts[:len(parts)-1] {
+			dir = joinSlash(dir, part)
+			matcher.load(filepath.Join(root, filepath.FromSlash(dir)), dir)
+		}
+	}
+
+	var comments []Comment
+	err := filepath.WalkDir(start, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip unreadable entries
+		}
+
+		rel := RelPath(root, p)
+		if d.IsDir() {
+			if p != start && (skippedDirs[d.Name()] || matcher.ignored(rel, true)) {
+				return filepath.SkipDir
+			}
+			matcher.load(p, rel)
+			return nil
+		}
+
+		if !d.Type().IsRegular() || matcher.ignored(rel, false) {
+			return nil
+		}
+		markers := markersForFile(p)
+		if markers == nil {
+			return nil
+		}
+
+		found, err := scanFile(p, rel, markers)
+		if err != nil {
+			return err
+		}
+		comments = append(comments, found...)
+		return nil
+	})
+
+	return comments, err
+}
+
+// joinSlash joins slash-separated relative paths
+func joinSlash(dir, name string) string {
+	if dir == "" {
+		return name
+	}
+	return dir + "/" + name
+}
+
+// scanFile extracts TODO-style comments from a single file
+func scanFile(p, rel string, markers []string) ([]Comment, error) {
+	info, err := os.Stat(p)
+	if err != nil || info.Size() > maxFileSize {
+		return nil, nil
+	}
+
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", rel, err)
+	}
+
+	// Skip binary files
+	head := data
+	if len(head) > 8000 {
+		head = head[:8000]
+	}
+	if bytes.IndexByte(head, 0) >= 0 {
+		return nil, nil
+	}
+
+	var comments []Comment
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), maxFileSize)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		if kind, text, ok := parseLine(scanner.Text(), markers); ok {
+			comments = append(comments, Comment{File: rel, Line: lineNo, Kind: kind, Text: text})
+		}
+	}
+
+	return comments, nil
+}
+
+// parseLine looks for a TODO-style tag directly after a comment marker
+func parseLine(line string, markers []string) (string, string, bool) {
+	if !strings.Contains(line, "TODO") && !strings.Contains(line, "FIXME") && !strings.Contains(line, "HACK") {
+		return "", "", false
+	}
+
+	for _, marker := range markers {
+		rest := line
+		for {
+			i := strings.Index(rest, marker)
+			if i < 0 {
+				break
+			}
+			// A "*" marker only counts at the start of a line, as in block comment bodies
+			if marker == "*" && strings.TrimSpace(rest[:i]) != "" {
+				break
+			}
+
+			after := strings.TrimLeft(rest[i+len(marker):], strings.TrimSpace(marker[len(marker)-1:]))
+			if m := tagPattern.FindStringSubmatch(after); m != nil {
+				text := strings.TrimSpace(m[2])
+				for _, closing := range closingMarkers {
+					if end := strings.Index(text, closing); end >= 0 {
+						text = strings.TrimSpace(text[:end])
+					}
+				}
+				return m[1], text, true
+			}
+			rest = rest[i+len(marker):]
+		}
+	}
+
+	return "", "", false
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"taskmaster/internal/models"
+	"time"
+)
+
+// statusFields change together and are merged as a unit so a merge never
+// produces, say, a completed task with 40% progress
+var statusFields = []string{"completed", "progress", "completed_at"}
+
+// MergeTaskJSON performs a three-way merge of a task file. Fields changed on
+// only one side take that side's value; fields changed on both sides take the
+// value from the side updated most recently. Annotations from both sides are
+// kept, and updated_at becomes the later of the two.
+func MergeTaskJSON(base, ours, theirs []byte) ([]byte, error) {
+	baseFields, err := decodeFields(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse base version: %w", err)
+	}
+	ourFields, err := decodeFields(ours)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse our version: %w", err)
+	}
+	theirFields, err := decodeFields(theirs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse their version: %w", err)
+	}
+
+	ourTime := updatedAt(ourFields)
+	theirTime := updatedAt(theirFields)
+	theirsNewer := theirTime.After(ourTime)
+
+	merged := make(map[string]json.RawMessage)
+	keys := make(map[string]bool)
+	for _, fields := range []map[string]json.RawMessage{baseFields, ourFields, theirFields} {
+		for key := range fields {
+			keys[key] = true
+		}
+	}
+
+	// Resolve the status fields together
+	ourStatus := changedAny(statusFields, baseFields, ourFields)
+	theirStatus := changedAny(statusFields, baseFields, theirFields)
+	for _, key := range statusFields {
+		delete(keys, key)
+		source := ourFields
+		if theirStatus && (!ourStatus || theirsNewer) {
+			source = theirFields
+		}
+		if value, ok := source[key]; ok {
+			merged[key] = value
+		}
+	}
+
+	for key := range keys {
+		base, inBase := baseFields[key]
+		our, inOurs := ourFields[key]
+		their, inTheirs := theirFields[key]
+		ourChanged := inOurs != inBase || !jsonEqual(our, base)
+		theirChanged := inTheirs != inBase || !jsonEqual(their, base)
+
+		value, present := our, inOurs
+		if theirChanged && (!ourChanged || theirsNewer) {
+			value, present = their, inTheirs
+		}
+		if present {
+			merged[key] = value
+		}
+	}
+
+	// Notes added on either branch are all kept
+	if annotations, ok, err := mergeAnnotations(ourFields["annotations"], theirFields["annotations"]); err != nil {
+		return nil, err
+	} else if ok {
+		merged["annotations"] = annotations
+	}
+
+	latest := ourTime
+	if theirsNewer {
+		latest = theirTime
+	}
+	if !latest.IsZero() {
+		merged["updated_at"], _ = json.Marshal(latest)
+	}
+
+	return marshalFields(merged)
+}
+
+// decodeFields parses a task file into its top-level fields; an empty file,
+// as git passes for a missing common ancestor, has no fields
+func decodeFields(data []byte) (map[string]json.RawMessage, error) {
+	fields := make(map[string]json.RawMessage)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return fields, nil
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// updatedAt returns the updated_at field, or the zero time if it is unusable
+func updatedAt(fields map[string]json.RawMessage) time.Time {
+	var t time.Time
+	if raw, ok := fields["updated_at"]; ok {
+		_ = json.Unmarshal(raw, &t)
+	}
+	return t
+}
+
+// changedAny reports whether any of the keys differs between base and side
+func changedAny(keys []string, base, side map[string]json.RawMessage) bool {
+	for _, key := range keys {
+		b, inBase := base[key]
+		s, inSide := side[key]
+		if inBase != inSide || !jsonEqual(b, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// jsonEqual compares two JSON values ignoring formatting
+func jsonEqual(a, b json.RawMessage) bool {
+	var bufA, bufB bytes.Buffer
+	if json.Compact(&bufA, a) != nil || json.Compact(&bufB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(bufA.Bytes(), bufB.Bytes())
+}
+
+// mergeAnnotations returns the union of both sides' annotations in entry order
+func mergeAnnotations(ours, theirs json.RawMessage) (json.RawMessage, bool, error) {
+	type annotation struct {
+		Entry       time.Time `json:"entry"`
+		Description string    `json:"description"`
+	}
+
+	var all []annotation
+	for _, raw := range []json.RawMessage{ours, theirs} {
+		if len(raw) == 0 {
+			continue
+		}
+		var list []annotation
+		if err := json.Unmarshal(raw, &list); err != nil {
+			return nil, false, fmt.Errorf("failed to parse annotations: %w", err)
+		}
+		all = append(all, list...)
+	}
+	if len(all) == 0 {
+		return nil, false, nil
+	}
+
+	var unique []annotation
+	seen := make(map[string]bool)
+	for _, a := range all {
+		key := a.Entry.UTC().Format(time.RFC3339Nano) + "\x00" + a.Description
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, a)
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		return unique[i].Entry.Before(unique[j].Entry)
+	})
+
+	data, err := json.Marshal(unique)
+	return data, err == nil, err
+}
+
+// marshalFields writes the merged fields in the same layout as saveTask
+func marshalFields(fields map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal task: %w", err)
+	}
+
+	var task models.Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, fmt.Errorf("failed to parse merged task: %w", err)
+	}
+
+	return json.MarshalIndent(&task, "", "  ")
+}
@@ -2,11 +2,11 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
-	"strconv"
 	"strings"
 	"sync"
 	"taskmaster/internal/models"
@@ -23,19 +23,53 @@ type Storage interface {
 	UpdateTask(*models.Task) error
 	DeleteTask(int64) error
 	CompleteTask(int64) error
+	ReopenTask(int64, int) error
 	UpdateTaskProgress(int64, int) error
 }
 
-// FileStorage implements Storage using file system
+// FileStorage implements Storage using file system.
+//
+// Each task lives in a file named after its UUID, so tasks created on
+// different branches never collide on disk. The numeric ID shown to users is
+// stored inside the file; two branches can hand out the same one, which the
+// reconcile command fixes after a merge.
 type FileStorage struct {
-	baseDir     string
-	tasksDir    string
-	counterFile string
-	mu          sync.Mutex
-	nextID      int64
+	baseDir  string
+	tasksDir string
+	mu       sync.Mutex
 }
 
-// NewFileStorage creates a new file storage instance
+// ErrNotFound is returned when no task has the requested ID
+var ErrNotFound = errors.New("not found")
+
+// ErrAmbiguousID is returned when a merge has left several tasks sharing an ID
+var ErrAmbiguousID = errors.New("ambiguous task ID")
+
+// ErrNeedsMigration is returned when changing tasks in a workspace written by
+// an older version, until Migrate has upgraded it
+var ErrNeedsMigration = errors.New("workspace was written by an older version of TaskMaster; run 'taskmaster migrate' to upgrade it")
+
+// notFound returns the error reported for a missing task
+func notFound(id int64) error {
+	return fmt.Errorf("task with ID %d %w", id, ErrNotFound)
+}
+
+// legacyCounterFile held the next task ID before tombstones were used
+const legacyCounterFile = "counter.json"
+
+// tombstoneDir holds a marker for each deleted task recording its ID, so the
+// ID is not handed out again. Markers are named after the task's UUID, so
+// deletions on different branches merge without conflicts.
+const tombstoneDir = "deleted"
+
+// tombstone is the marker left by a deleted task
+type tombstone struct {
+	ID        int64     `json:"id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// NewFileStorage creates a new file storage instance for the workspace in
+// targetDir. Nothing is created on disk; see CreateWorkspace.
 func NewFileStorage(targetDir string) (*FileStorage, error) {
 	// If no target directory provided, use current directory
 	if targetDir == "" {
@@ -47,17 +81,10 @@ func NewFileStorage(targetDir string) (*FileStorage, error) {
 		targetDir = cwd
 	}
 
-	// Create the tasks directory
-	tasksDir := filepath.Join(targetDir, ".taskmaster")
-	err := os.MkdirAll(tasksDir, 0755)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create tasks directory: %w", err)
-	}
-
+	tasksDir := filepath.Join(targetDir, DirName)
 	storage := &FileStorage{
-		baseDir:     targetDir,
-		tasksDir:    tasksDir,
-		counterFile: filepath.Join(tasksDir, "counter.json"),
+		baseDir:  targetDir,
+		tasksDir: tasksDir,
 	}
 
 	return storage, nil
@@ -68,53 +95,141 @@ func (s *FileStorage) Init() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Create the tasks directory if it doesn't exist
-	if err := os.MkdirAll(s.tasksDir, 0755); err != nil {
-		return fmt.Errorf("failed to create tasks directory: %w", err)
+	// The workspace must have been created with CreateWorkspace
+	info, err := os.Stat(s.tasksDir)
+	if err != nil || !info.IsDir() {
+		return fmt.Errorf("%w in %s (run 'taskmaster init' to create one)", ErrNoWorkspace, s.baseDir)
 	}
+	return nil
+}
 
-	// Read counter file or initialize it
-	counter, err := os.ReadFile(s.counterFile)
+// NeedsMigration reports whether the workspace holds files written by an
+// older version: the ID counter, or task files not named after their UUID
+func (s *FileStorage) NeedsMigration() (bool, error) {
+	files, err := os.ReadDir(s.tasksDir)
 	if err != nil {
-		if os.IsNotExist(err) {
-			// Create a new counter starting at 1
-			s.nextID = 1
-			return s.saveCounter()
+		return false, fmt.Errorf("failed to read tasks directory: %w", err)
+	}
+	for _, file := range files {
+		if file.Name() == legacyCounterFile {
+			return true, nil
+		}
+		if isTaskFile(file) && !models.ValidUUID(strings.TrimSuffix(strings.TrimPrefix(file.Name(), "task_"), ".json")) {
+			return true, nil
 		}
-		return fmt.Errorf("failed to read counter file: %w", err)
 	}
+	return false, nil
+}
 
-	// Parse counter value
-	var counterData struct {
-		NextID int64 `json:"next_id"`
+// requireMigrated keeps changes out of a workspace that has not been
+// upgraded, where they could leave a task in two files
+func (s *FileStorage) requireMigrated() error {
+	legacy, err := s.NeedsMigration()
+	if err != nil {
+		return err
 	}
-	if err := json.Unmarshal(counter, &counterData); err != nil {
-		return fmt.Errorf("failed to parse counter file: %w", err)
+	if legacy {
+		return ErrNeedsMigration
 	}
-
-	s.nextID = counterData.NextID
 	return nil
 }
 
-// saveCounter saves the current counter value
-func (s *FileStorage) saveCounter() error {
-	counterData := struct {
-		NextID int64 `json:"next_id"`
-	}{
-		NextID: s.nextID,
-	}
+// Migrate upgrades task files written by older versions, returning how many
+// files were rewritten. It only runs when asked to, from the init and migrate
+// commands, so reading tasks never changes files.
+func (s *FileStorage) Migrate() (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	data, err := json.MarshalIndent(counterData, "", "  ")
+	files, err := os.ReadDir(s.tasksDir)
 	if err != nil {
-		return fmt.Errorf("failed to marshal counter: %w", err)
+		return 0, fmt.Errorf("failed to read tasks directory: %w", err)
 	}
 
-	err = os.WriteFile(s.counterFile, data, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write counter file: %w", err)
+	upgraded := 0
+
+	for _, file := range files {
+		if !isTaskFile(file) {
+			continue
+		}
+
+		path := filepath.Join(s.tasksDir, file.Name())
+		task, err := readTaskFile(path)
+		if err != nil {
+			continue // Leave unreadable files untouched
+		}
+
+		changed := false
+
+		// Completed tasks from before completion times were recorded use
+		// their last update as the completion time
+		if task.Completed && task.CompletionTime().IsZero() {
+			task.MarkCompleted(task.UpdatedAt)
+			changed = true
+		}
+
+		// Tasks from before UUIDs were assigned get one now, and tasks
+		// imported with another tool's identifier keep it as their external ID
+		if task.UUID != "" && !models.ValidUUID(task.UUID) {
+			task.ExternalID = task.UUID
+			task.UUID = ""
+		}
+		if task.UUID == "" {
+			task.UUID = models.NewUUID()
+			changed = true
+		}
+
+		// Files named after the numeric ID or a non-UUID identifier move to
+		// their UUID name
+		name, _ := TaskFilename(task.UUID)
+		misnamed := file.Name() != name
+
+		if changed || misnamed {
+			if err := s.saveTask(task); err != nil {
+				return upgraded, err
+			}
+			upgraded++
+		}
+		if misnamed {
+			if err := os.Remove(path); err != nil {
+				return upgraded, fmt.Errorf("failed to remove old task file: %w", err)
+			}
+		}
 	}
 
-	return nil
+	// The ID counter caused merge conflicts; a tombstone keeps its IDs used
+	counterPath := filepath.Join(s.tasksDir, legacyCounterFile)
+	if data, err := os.ReadFile(counterPath); err == nil {
+		var counter struct {
+			NextID int64 `json:"next_id"`
+		}
+		if json.Unmarshal(data, &counter) == nil && counter.NextID > 1 {
+			if err := s.writeTombstone(models.NewUUID(), counter.NextID-1); err != nil {
+				return upgraded, err
+			}
+		}
+		if err := os.Remove(counterPath); err != nil {
+			return upgraded, fmt.Errorf("failed to remove old counter file: %w", err)
+		}
+		upgraded++
+	}
+
+	return upgraded, nil
+}
+
+// isTaskFile reports whether a directory entry is a task file
+func isTaskFile(file os.DirEntry) bool {
+	return !file.IsDir() && strings.HasPrefix(file.Name(), "task_") && strings.HasSuffix(file.Name(), ".json")
+}
+
+// Root returns the workspace directory that contains the tasks directory
+func (s *FileStorage) Root() string {
+	return s.baseDir
+}
+
+// Dir returns the directory where tasks are stored
+func (s *FileStorage) Dir() string {
+	return s.tasksDir
 }
 
 // Close closes the storage
@@ -128,54 +243,194 @@ func (s *FileStorage) CreateTask(task *models.Task) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	// Set ID and timestamps
-	task.ID = s.nextID
-	s.nextID++
+	if err := s.requireMigrated(); err != nil {
+		return err
+	}
 
-	now := time.Now()
-	task.CreatedAt = now
-	task.UpdatedAt = now
+	tasks, err := s.loadTasks()
+	if err != nil {
+		return err
+	}
 
-	// Save the counter
-	if err := s.saveCounter(); err != nil {
+	// Set ID, UUID and timestamps; the ID follows the highest one ever used
+	deleted, err := s.loadTombstones()
+	if err != nil {
 		return err
 	}
+	task.ID = max(MaxID(tasks), maxTombstoneID(deleted)) + 1
+	if task.UUID == "" {
+		task.UUID = models.NewUUID()
+	}
+
+	// Imported tasks keep their original creation and modification times
+	now := time.Now()
+	if task.CreatedAt.IsZero() {
+		task.CreatedAt = now
+	}
+	if task.UpdatedAt.IsZero() {
+		task.UpdatedAt = now
+	}
 
 	// Save the task
-	return s.saveTask(task)
+	if err := s.saveTask(task); err != nil {
+		return err
+	}
+
+	// The new task now holds the highest ID, so the markers are not needed
+	for name := range deleted {
+		os.Remove(filepath.Join(s.tasksDir, tombstoneDir, name))
+	}
+	return nil
+}
+
+// NextID returns the ID the next created task will get, which is above every
+// ID in use or held by a deleted task
+func (s *FileStorage) NextID() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks, err := s.loadTasks()
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := s.loadTombstones()
+	if err != nil {
+		return 0, err
+	}
+	return max(MaxID(tasks), maxTombstoneID(deleted)) + 1, nil
+}
+
+// writeTombstone records that the task with the given UUID and ID was deleted
+func (s *FileStorage) writeTombstone(uuid string, id int64) error {
+	name, err := TaskFilename(uuid)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(s.tasksDir, tombstoneDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create deleted tasks directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(tombstone{ID: id, DeletedAt: time.Now()}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, name), data)
+}
+
+// loadTombstones reads the markers of deleted tasks, keyed by file name
+func (s *FileStorage) loadTombstones() (map[string]tombstone, error) {
+	files, err := os.ReadDir(filepath.Join(s.tasksDir, tombstoneDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read deleted tasks directory: %w", err)
+	}
+
+	deleted := make(map[string]tombstone)
+	for _, file := range files {
+		if !isTaskFile(file) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.tasksDir, tombstoneDir, file.Name()))
+		if err != nil {
+			continue
+		}
+		var t tombstone
+		if json.Unmarshal(data, &t) == nil {
+			deleted[file.Name()] = t
+		}
+	}
+	return deleted, nil
+}
+
+// maxTombstoneID returns the highest ID held by a deleted task
+func maxTombstoneID(deleted map[string]tombstone) int64 {
+	var maxID int64
+	for _, t := range deleted {
+		maxID = max(maxID, t.ID)
+	}
+	return maxID
+}
+
+// MaxID returns the highest display ID among the tasks
+func MaxID(tasks []*models.Task) int64 {
+	var maxID int64
+	for _, task := range tasks {
+		if task.ID > maxID {
+			maxID = task.ID
+		}
+	}
+	return maxID
 }
 
-// getTaskFilename returns the filename for a task
-func (s *FileStorage) getTaskFilename(id int64) string {
-	return filepath.Join(s.tasksDir, fmt.Sprintf("task_%d.json", id))
+// TaskFilename returns the file name used for the task with the given UUID.
+// Anything other than a UUID is rejected, since it ends up in a file path.
+func TaskFilename(uuid string) (string, error) {
+	if !models.ValidUUID(uuid) {
+		return "", fmt.Errorf("invalid task UUID %q", uuid)
+	}
+	return fmt.Sprintf("task_%s.json", uuid), nil
 }
 
-// saveTask saves a task to a file
+// taskPath returns the path of the file of the task with the given UUID
+func (s *FileStorage) taskPath(uuid string) (string, error) {
+	name, err := TaskFilename(uuid)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.tasksDir, name), nil
+}
+
+// saveTask saves a task to its file, replacing it atomically
 func (s *FileStorage) saveTask(task *models.Task) error {
-	filename := s.getTaskFilename(task.ID)
+	if task.UUID == "" {
+		task.UUID = models.NewUUID()
+	}
+	filename, err := s.taskPath(task.UUID)
+	if err != nil {
+		return err
+	}
 
 	data, err := json.MarshalIndent(task, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal task: %w", err)
 	}
 
-	err = os.WriteFile(filename, data, 0644)
+	return writeFileAtomic(filename, data)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place
+func writeFileAtomic(filename string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".tmp_"+filepath.Base(filename))
 	if err != nil {
 		return fmt.Errorf("failed to write task file: %w", err)
 	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write task file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write task file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to write task file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("failed to write task file: %w", err)
+	}
 
 	return nil
 }
 
-// GetTask retrieves a task by ID
-func (s *FileStorage) GetTask(id int64) (*models.Task, error) {
-	filename := s.getTaskFilename(id)
-
-	data, err := os.ReadFile(filename)
+// readTaskFile reads and parses a single task file
+func readTaskFile(path string) (*models.Task, error) {
+	data, err := os.ReadFile(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("task with ID %d not found", id)
-		}
 		return nil, fmt.Errorf("failed to read task file: %w", err)
 	}
 
@@ -183,12 +438,16 @@ func (s *FileStorage) GetTask(id int64) (*models.Task, error) {
 	if err := json.Unmarshal(data, &task); err != nil {
 		return nil, fmt.Errorf("failed to parse task file: %w", err)
 	}
+	// Earlier versions stored the zero time for tasks that were never completed
+	if task.CompletedAt != nil && task.CompletedAt.IsZero() {
+		task.CompletedAt = nil
+	}
 
 	return &task, nil
 }
 
-// GetAllTasks retrieves all tasks
-func (s *FileStorage) GetAllTasks() ([]*models.Task, error) {
+// loadTasks reads every task file, skipping files that cannot be loaded
+func (s *FileStorage) loadTasks() ([]*models.Task, error) {
 	var tasks []*models.Task
 
 	// Read all task files
@@ -198,19 +457,12 @@ func (s *FileStorage) GetAllTasks() ([]*models.Task, error) {
 	}
 
 	for _, file := range files {
-		if file.IsDir() || !strings.HasPrefix(file.Name(), "task_") || !strings.HasSuffix(file.Name(), ".json") {
-			continue // Skip directories and non-task files
-		}
-
-		// Extract ID from filename
-		idStr := strings.TrimPrefix(file.Name(), "task_")
-		idStr = strings.TrimSuffix(idStr, ".json")
-		id, err := strconv.ParseInt(idStr, 10, 64)
-		if err != nil {
-			continue // Skip files with invalid ID format
+		// Skip directories and non-task files
+		if !isTaskFile(file) {
+			continue
 		}
 
-		task, err := s.GetTask(id)
+		task, err := readTaskFile(filepath.Join(s.tasksDir, file.Name()))
 		if err != nil {
 			continue // Skip tasks that can't be loaded
 		}
@@ -218,9 +470,47 @@ func (s *FileStorage) GetAllTasks() ([]*models.Task, error) {
 		tasks = append(tasks, task)
 	}
 
-	// Sort tasks by ID
+	return tasks, nil
+}
+
+// GetTask retrieves a task by ID
+func (s *FileStorage) GetTask(id int64) (*models.Task, error) {
+	tasks, err := s.loadTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []*models.Task
+	for _, task := range tasks {
+		if task.ID == id {
+			found = append(found, task)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, notFound(id)
+	case 1:
+		return found[0], nil
+	default:
+		return nil, fmt.Errorf("%w: task ID %d is shared by %d tasks, run 'taskmaster reconcile' to renumber them",
+			ErrAmbiguousID, id, len(found))
+	}
+}
+
+// GetAllTasks retrieves all tasks
+func (s *FileStorage) GetAllTasks() ([]*models.Task, error) {
+	tasks, err := s.loadTasks()
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort tasks by ID, oldest first where a merge left IDs shared
 	sort.Slice(tasks, func(i, j int) bool {
-		return tasks[i].ID < tasks[j].ID
+		if tasks[i].ID != tasks[j].ID {
+			return tasks[i].ID < tasks[j].ID
+		}
+		return tasks[i].CreatedAt.Before(tasks[j].CreatedAt)
 	})
 
 	return tasks, nil
@@ -228,50 +518,98 @@ func (s *FileStorage) GetAllTasks() ([]*models.Task, error) {
 
 // UpdateTask updates an existing task
 func (s *FileStorage) UpdateTask(task *models.Task) error {
-	// Check if task exists
-	_, err := s.GetTask(task.ID)
-	if err != nil {
+	task.UpdatedAt = time.Now()
+	return s.SaveTask(task)
+}
+
+// SaveTask stores an existing task as given, keeping its modification time,
+// for changes such as imports that happened elsewhere
+func (s *FileStorage) SaveTask(task *models.Task) error {
+	if err := s.requireMigrated(); err != nil {
 		return err
 	}
 
-	// Update timestamp
-	task.UpdatedAt = time.Now()
+	// Check if task exists, by UUID when known since display IDs may be shared
+	if task.UUID != "" {
+		path, err := s.taskPath(task.UUID)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(path); err != nil {
+			if os.IsNotExist(err) {
+				return notFound(task.ID)
+			}
+			return fmt.Errorf("failed to access task file: %w", err)
+		}
+	} else {
+		existing, err := s.GetTask(task.ID)
+		if err != nil {
+			return err
+		}
+		task.UUID = existing.UUID
+	}
 
-	// Save the updated task
 	return s.saveTask(task)
 }
 
 // DeleteTask deletes a task by ID
 func (s *FileStorage) DeleteTask(id int64) error {
-	filename := s.getTaskFilename(id)
+	if err := s.requireMigrated(); err != nil {
+		return err
+	}
 
-	// Check if file exists
-	_, err := os.Stat(filename)
+	task, err := s.GetTask(id)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return fmt.Errorf("task with ID %d not found", id)
-		}
-		return fmt.Errorf("failed to access task file: %w", err)
+		return err
 	}
 
 	// Delete the file
-	err = os.Remove(filename)
+	path, err := s.taskPath(task.UUID)
 	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return notFound(id)
+		}
 		return fmt.Errorf("failed to delete task file: %w", err)
 	}
 
-	return nil
+	// Keep the ID from being reused, so references to it stay unambiguous
+	return s.writeTombstone(task.UUID, task.ID)
 }
 
 // CompleteTask marks a task as completed
 func (s *FileStorage) CompleteTask(id int64) error {
+	if err := s.requireMigrated(); err != nil {
+		return err
+	}
+
+	task, err := s.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	task.MarkCompleted(now)
+	task.UpdatedAt = now
+
+	return s.saveTask(task)
+}
+
+// ReopenTask marks a completed task as open again with the given progress
+func (s *FileStorage) ReopenTask(id int64, progress int) error {
+	if err := s.requireMigrated(); err != nil {
+		return err
+	}
+
 	task, err := s.GetTask(id)
 	if err != nil {
 		return err
 	}
 
-	task.Completed = true
-	task.Progress = 100
+	task.MarkOpen()
+	task.Progress = progress
 	task.UpdatedAt = time.Now()
 
 	return s.saveTask(task)
@@ -279,17 +617,24 @@ func (s *FileStorage) CompleteTask(id int64) error {
 
 // UpdateTaskProgress updates the progress of a task
 func (s *FileStorage) UpdateTaskProgress(id int64, progress int) error {
+	if err := s.requireMigrated(); err != nil {
+		return err
+	}
+
 	task, err := s.GetTask(id)
 	if err != nil {
 		return err
 	}
 
+	now := time.Now()
 	task.Progress = progress
-	task.UpdatedAt = time.Now()
+	task.UpdatedAt = now
 
-	// If progress is 100%, mark as completed
+	// Progress of 100% completes the task, anything less reopens it
 	if progress == 100 {
-		task.Completed = true
+		task.MarkCompleted(now)
+	} else {
+		task.MarkOpen()
 	}
 
 	return s.saveTask(task)
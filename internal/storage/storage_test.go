@@ -0,0 +1,208 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"taskmaster/internal/models"
+	"testing"
+)
+
+// newTestStorage returns storage for a new workspace in a temporary directory
+func newTestStorage(t *testing.T) *FileStorage {
+	t.Helper()
+
+	root := t.TempDir()
+	if _, err := CreateWorkspace(root); err != nil {
+		t.Fatalf("CreateWorkspace() error: %v", err)
+	}
+	s, err := NewFileStorage(root)
+	if err != nil {
+		t.Fatalf("NewFileStorage() error: %v", err)
+	}
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init() error: %v", err)
+	}
+	return s
+}
+
+// createTask adds a task with the given title and returns it
+func createTask(t *testing.T, s *FileStorage, title string) *models.Task {
+	t.Helper()
+
+	task := &models.Task{Title: title}
+	if err := s.CreateTask(task); err != nil {
+		t.Fatalf("CreateTask(%q) error: %v", title, err)
+	}
+	return task
+}
+
+func TestTaskFilename(t *testing.T) {
+	tests := []struct {
+		uuid    string
+		want    string
+		wantErr bool
+	}{
+		{uuid: "0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b2a10", want: "task_0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b2a10.json"},
+		{uuid: "../../pwned", wantErr: true},
+		{uuid: "abc/def@example.com", wantErr: true},
+		{uuid: "0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b2a1/", wantErr: true},
+		{uuid: "0b9c3f4e2d1a4c8b9e7f6a5d4c3b2a10", wantErr: true},
+		{uuid: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := TaskFilename(tt.uuid)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("TaskFilename(%q) = %q, want an error", tt.uuid, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("TaskFilename(%q) = %q, %v; want %q", tt.uuid, got, err, tt.want)
+		}
+	}
+}
+
+func TestCreateTaskRejectsInvalidUUID(t *testing.T) {
+	s := newTestStorage(t)
+
+	task := &models.Task{Title: "escape", UUID: "../../pwned"}
+	if err := s.CreateTask(task); err == nil {
+		t.Fatal("CreateTask() accepted a UUID containing a path")
+	}
+	if _, err := os.Stat(filepath.Join(s.Root(), "pwned.json")); !os.IsNotExist(err) {
+		t.Errorf("a file was written outside the tasks directory")
+	}
+}
+
+func TestCreateTaskDoesNotReuseIDs(t *testing.T) {
+	s := newTestStorage(t)
+
+	createTask(t, s, "first")
+	second := createTask(t, s, "second")
+	if second.ID != 2 {
+		t.Fatalf("second task has ID %d, want 2", second.ID)
+	}
+
+	if err := s.DeleteTask(second.ID); err != nil {
+		t.Fatalf("DeleteTask() error: %v", err)
+	}
+	if next, err := s.NextID(); err != nil || next != 3 {
+		t.Errorf("NextID() = %d, %v; want 3", next, err)
+	}
+
+	third := createTask(t, s, "third")
+	if third.ID != 3 {
+		t.Errorf("task created after a deletion has ID %d, want 3", third.ID)
+	}
+
+	// Once a higher ID is in use, the marker is no longer needed
+	markers, _ := os.ReadDir(filepath.Join(s.Dir(), tombstoneDir))
+	if len(markers) != 0 {
+		t.Errorf("found %d deleted-task markers, want none", len(markers))
+	}
+}
+
+func TestTombstonesFromOtherBranchesAreHonoured(t *testing.T) {
+	s := newTestStorage(t)
+	createTask(t, s, "first")
+
+	// A task deleted on another branch arrives as just its marker
+	if err := s.writeTombstone(models.NewUUID(), 7); err != nil {
+		t.Fatalf("writeTombstone() error: %v", err)
+	}
+	if task := createTask(t, s, "next"); task.ID != 8 {
+		t.Errorf("task has ID %d, want 8", task.ID)
+	}
+}
+
+func TestLegacyWorkspaceMigration(t *testing.T) {
+	s := newTestStorage(t)
+
+	legacy := []byte(`{"id": 3, "title": "old", "completed": true, "updated_at": "2024-05-01T10:00:00Z"}`)
+	if err := os.WriteFile(filepath.Join(s.Dir(), "task_3.json"), legacy, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(s.Dir(), legacyCounterFile), []byte(`{"next_id": 5}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if legacy, err := s.NeedsMigration(); err != nil || !legacy {
+		t.Fatalf("NeedsMigration() = %v, %v; want true", legacy, err)
+	}
+	if err := s.CreateTask(&models.Task{Title: "new"}); !errors.Is(err, ErrNeedsMigration) {
+		t.Fatalf("CreateTask() error = %v, want ErrNeedsMigration", err)
+	}
+
+	if _, err := s.Migrate(); err != nil {
+		t.Fatalf("Migrate() error: %v", err)
+	}
+	if legacy, err := s.NeedsMigration(); err != nil || legacy {
+		t.Errorf("NeedsMigration() after Migrate = %v, %v; want false", legacy, err)
+	}
+
+	task, err := s.GetTask(3)
+	if err != nil {
+		t.Fatalf("GetTask(3) error: %v", err)
+	}
+	if !models.ValidUUID(task.UUID) {
+		t.Errorf("migrated task has UUID %q", task.UUID)
+	}
+	if task.CompletionTime().IsZero() {
+		t.Error("migrated completed task has no completion time")
+	}
+
+	// IDs handed out by the old counter stay used
+	if next := createTask(t, s, "new"); next.ID != 5 {
+		t.Errorf("task created after migration has ID %d, want 5", next.ID)
+	}
+}
+
+func TestOpenTasksHaveNoCompletionTime(t *testing.T) {
+	s := newTestStorage(t)
+	task := createTask(t, s, "open")
+
+	path, _ := s.taskPath(task.UUID)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "completed_at") {
+		t.Errorf("open task saved with a completion time:\n%s", data)
+	}
+
+	// Files from earlier versions hold the zero time instead
+	legacy := strings.Replace(string(data), `"completed": false,`, `"completed": false, "completed_at": "0001-01-01T00:00:00Z",`, 1)
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := s.GetTask(task.ID); err != nil || got.CompletedAt != nil {
+		t.Errorf("GetTask() = %+v, %v; want no completion time", got, err)
+	}
+
+	if err := s.CompleteTask(task.ID); err != nil {
+		t.Fatalf("CompleteTask() error: %v", err)
+	}
+	if got, _ := s.GetTask(task.ID); got.CompletionTime().IsZero() {
+		t.Error("completed task has no completion time")
+	}
+	if err := s.ReopenTask(task.ID, 50); err != nil {
+		t.Fatalf("ReopenTask() error: %v", err)
+	}
+	if got, _ := s.GetTask(task.ID); got.CompletedAt != nil {
+		t.Errorf("reopened task still has completion time %v", got.CompletedAt)
+	}
+}
+
+func TestValidUUID(t *testing.T) {
+	if id := models.NewUUID(); !models.ValidUUID(id) {
+		t.Errorf("ValidUUID(%q) = false for a generated UUID", id)
+	}
+	for _, s := range []string{"", "task-1", "../0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b", "0b9c3f4e-2d1a-4c8b-9e7f-6a5d4c3b2a1g"} {
+		if models.ValidUUID(s) {
+			t.Errorf("ValidUUID(%q) = true", s)
+		}
+	}
+}
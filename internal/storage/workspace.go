@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DirName is the name of the directory that holds a workspace's tasks
+const DirName = ".taskmaster"
+
+// EnvDir names the environment variable that selects the workspace directory
+const EnvDir = "TASKMASTER_DIR"
+
+// ErrNoWorkspace is returned when no workspace can be found
+var ErrNoWorkspace = errors.New("no taskmaster workspace found")
+
+// FindWorkspace walks up from start, like git, and returns the first directory
+// that contains a .taskmaster directory
+func FindWorkspace(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", start, err)
+	}
+
+	for {
+		if isWorkspace(dir) {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("%w in %s or any parent directory (run 'taskmaster init' to create one)", ErrNoWorkspace, start)
+		}
+		dir = parent
+	}
+}
+
+// WorkspaceRoot normalizes an explicitly chosen workspace directory, accepting
+// either the workspace root or its .taskmaster directory
+func WorkspaceRoot(dir string) (string, error) {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	if filepath.Base(root) == DirName {
+		root = filepath.Dir(root)
+	}
+	return root, nil
+}
+
+// ResolveWorkspace finds the workspace to use. An explicit directory, such as
+// one given with --dir or TASKMASTER_DIR, is used as is and must already be a
+// workspace; otherwise the search walks up from cwd.
+func ResolveWorkspace(explicit, cwd string) (string, error) {
+	if explicit == "" {
+		return FindWorkspace(cwd)
+	}
+
+	root, err := WorkspaceRoot(explicit)
+	if err != nil {
+		return "", err
+	}
+	if !isWorkspace(root) {
+		return "", fmt.Errorf("%w in %s (run 'taskmaster init' to create one)", ErrNoWorkspace, root)
+	}
+	return root, nil
+}
+
+// CreateWorkspace creates the .taskmaster directory in root, reporting
+// whether it was newly created
+func CreateWorkspace(root string) (bool, error) {
+	if isWorkspace(root) {
+		return false, nil
+	}
+	if err := os.MkdirAll(filepath.Join(root, DirName), 0755); err != nil {
+		return false, fmt.Errorf("failed to create tasks directory: %w", err)
+	}
+	return true, nil
+}
+
+// isWorkspace reports whether dir contains a .taskmaster directory
+func isWorkspace(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, DirName))
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,334 @@
+// Package webhook delivers signed task events to HTTP endpoints through a
+// queue kept on disk, so deliveries survive the process that created them
+package webhook
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-TaskMaster-Event"
+	HeaderDelivery  = "X-TaskMaster-Delivery"
+	HeaderSignature = "X-TaskMaster-Signature"
+)
+
+// Retry schedule: the delay doubles after every failed attempt, starting at
+// BaseDelay and capped at MaxDelay, until MaxAttempts have been made
+const (
+	BaseDelay   = 30 * time.Second
+	MaxDelay    = time.Hour
+	MaxAttempts = 10
+)
+
+// lockTimeout is how long a delivery lock is honoured before it is assumed to
+// have been left behind by a crashed process
+const lockTimeout = 5 * time.Minute
+
+// Delivery is a queued request to one endpoint
+type Delivery struct {
+	ID          string          `json:"id"`
+	Event       string          `json:"event"`
+	URL         string          `json:"url"`
+	Signature   string          `json:"signature,omitempty"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt"`
+	LastError   string          `json:"last_error,omitempty"`
+}
+
+// Result reports the outcome of one delivery attempt
+type Result struct {
+	Delivery *Delivery
+	Err      error
+	// Dropped is set when the delivery failed for the last time
+	Dropped bool
+}
+
+// Queue keeps deliveries as JSON files in the pending subdirectory of its
+// directory. Deliveries that run out of attempts move to the failed one.
+type Queue struct {
+	dir string
+}
+
+// NewQueue returns the queue kept in dir; nothing is created until a
+// delivery is added
+func NewQueue(dir string) *Queue {
+	return &Queue{dir: dir}
+}
+
+// Dir returns the directory holding the queue, which callers may also use
+// for their own delivery state
+func (q *Queue) Dir() string {
+	return q.dir
+}
+
+// pendingDir returns the directory of deliveries waiting to be sent
+func (q *Queue) pendingDir() string {
+	return filepath.Join(q.dir, "pending")
+}
+
+// failedDir returns the directory of deliveries that were given up on
+func (q *Queue) failedDir() string {
+	return filepath.Join(q.dir, "failed")
+}
+
+// Sign returns the signature header value for a payload: the hex HMAC-SHA256
+// of the body keyed with the endpoint's secret
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the valid signature of body
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// Enqueue stores a new delivery of payload to url, due immediately. The
+// payload is signed now, so the secret itself is never written to disk.
+func (q *Queue) Enqueue(event, url, secret string, payload []byte) (*Delivery, error) {
+	var body bytes.Buffer
+	if err := json.Compact(&body, payload); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+
+	d := &Delivery{
+		ID:          newID(),
+		Event:       event,
+		URL:         url,
+		Payload:     body.Bytes(),
+		CreatedAt:   time.Now(),
+		NextAttempt: time.Now(),
+	}
+	if secret != "" {
+		d.Signature = Sign(secret, body.Bytes())
+	}
+	if err := q.save(d, q.pendingDir()); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Pending returns the queued deliveries, oldest first
+func (q *Queue) Pending() ([]*Delivery, error) {
+	return q.load(q.pendingDir())
+}
+
+// Failed returns the deliveries that ran out of attempts, oldest first
+func (q *Queue) Failed() ([]*Delivery, error) {
+	return q.load(q.failedDir())
+}
+
+// Deliver sends every delivery that is due, removing the ones that succeed
+// and rescheduling the rest. Only one process delivers at a time; if another
+// holds the lock, Deliver returns without doing anything.
+func (q *Queue) Deliver(client *http.Client, now time.Time) ([]Result, error) {
+	pending, err := q.Pending()
+	if err != nil || len(pending) == 0 {
+		return nil, err
+	}
+
+	unlock, ok := q.lock()
+	if !ok {
+		return nil, nil
+	}
+	defer unlock()
+
+	var results []Result
+	for _, d := range pending {
+		if d.NextAttempt.After(now) {
+			continue
+		}
+
+		err := send(client, d)
+		if err == nil {
+			if err := os.Remove(q.path(d, q.pendingDir())); err != nil && !os.IsNotExist(err) {
+				return results, fmt.Errorf("failed to remove delivered webhook: %w", err)
+			}
+			results = append(results, Result{Delivery: d})
+			continue
+		}
+
+		d.Attempts++
+		d.LastError = err.Error()
+		result := Result{Delivery: d, Err: err}
+
+		if d.Attempts >= MaxAttempts {
+			result.Dropped = true
+			if err := q.save(d, q.failedDir()); err != nil {
+				return results, err
+			}
+			os.Remove(q.path(d, q.pendingDir()))
+		} else {
+			d.NextAttempt = now.Add(Backoff(d.Attempts))
+			if err := q.save(d, q.pendingDir()); err != nil {
+				return results, err
+			}
+		}
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Backoff returns the delay before the next attempt after the given number
+// of failed attempts
+func Backoff(attempts int) time.Duration {
+	delay := BaseDelay
+	for i := 1; i < attempts && delay < MaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > MaxDelay {
+		delay = MaxDelay
+	}
+	return delay
+}
+
+// send posts a delivery, treating any 2xx response as success
+func send(client *http.Client, d *Delivery) error {
+	// The queue file stores the payload indented; send it compact, as it
+	// was when it was signed
+	var body bytes.Buffer
+	if err := json.Compact(&body, d.Payload); err != nil {
+		return fmt.Errorf("invalid payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, d.URL, bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TaskMaster-Webhook")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.ID)
+	if d.Signature != "" {
+		req.Header.Set(HeaderSignature, d.Signature)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return nil
+}
+
+// lock takes the delivery lock, returning a function that releases it
+func (q *Queue) lock() (func(), bool) {
+	if err := os.MkdirAll(q.dir, 0755); err != nil {
+		return nil, false
+	}
+	path := filepath.Join(q.dir, "deliver.lock")
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockTimeout {
+		os.Remove(path)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, false
+	}
+	fmt.Fprintf(file, "%d\n", os.Getpid())
+	file.Close()
+
+	return func() { os.Remove(path) }, true
+}
+
+// path returns the file holding a delivery in dir
+func (q *Queue) path(d *Delivery, dir string) string {
+	return filepath.Join(dir, d.ID+".json")
+}
+
+// save writes a delivery to dir, creating the queue directory on first use
+func (q *Queue) save(d *Delivery, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create webhook queue: %w", err)
+	}
+	q.ignore()
+
+	// Keep the payload byte for byte as signed, without escaping <, > and &
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(d); err != nil {
+		return fmt.Errorf("failed to marshal webhook delivery: %w", err)
+	}
+
+	tmp := q.path(d, dir) + ".tmp"
+	if err := os.WriteFile(tmp, data.Bytes(), 0600); err != nil {
+		return fmt.Errorf("failed to write webhook delivery: %w", err)
+	}
+	if err := os.Rename(tmp, q.path(d, dir)); err != nil {
+		return fmt.Errorf("failed to write webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// ignore keeps the queue directory, which is local delivery state, out of
+// version control
+func (q *Queue) ignore() {
+	path := filepath.Join(q.dir, ".gitignore")
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		os.WriteFile(path, []byte("*\n"), 0644)
+	}
+}
+
+// load reads the deliveries stored in dir, oldest first
+func (q *Queue) load(dir string) ([]*Delivery, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read webhook queue: %w", err)
+	}
+
+	var deliveries []*Delivery
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var d Delivery
+		if err := json.Unmarshal(data, &d); err != nil {
+			continue
+		}
+		deliveries = append(deliveries, &d)
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.Before(deliveries[j].CreatedAt)
+	})
+	return deliveries, nil
+}
+
+// newID returns a random delivery ID
+func newID() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	body := []byte(`{"event":"created"}`)
+	signature := Sign("s3cret", body)
+
+	if !strings.HasPrefix(signature, "sha256=") {
+		t.Fatalf("Sign() = %q, want a sha256= prefix", signature)
+	}
+	if !Verify("s3cret", body, signature) {
+		t.Error("Verify() rejected a valid signature")
+	}
+	if Verify("other", body, signature) {
+		t.Error("Verify() accepted a signature made with another secret")
+	}
+	if Verify("s3cret", []byte(`{"event":"completed"}`), signature) {
+		t.Error("Verify() accepted a signature of another body")
+	}
+}
+
+func TestDeliverSendsSignedPayload(t *testing.T) {
+	var got struct {
+		body      []byte
+		event     string
+		signature string
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.body, _ = io.ReadAll(r.Body)
+		got.event = r.Header.Get(HeaderEvent)
+		got.signature = r.Header.Get(HeaderSignature)
+	}))
+	defer server.Close()
+
+	q := NewQueue(t.TempDir())
+	payload := []byte("{\n  \"event\": \"created\",\n  \"task\": {\"title\": \"<b>\"}\n}")
+	if _, err := q.Enqueue("created", server.URL, "s3cret", payload); err != nil {
+		t.Fatalf("Enqueue() error: %v", err)
+	}
+
+	results, err := q.Deliver(server.Client(), time.Now())
+	if err != nil {
+		t.Fatalf("Deliver() error: %v", err)
+	}
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("Deliver() results = %+v, want one success", results)
+	}
+
+	if want := `{"event":"created","task":{"title":"<b>"}}`; string(got.body) != want {
+		t.Errorf("body = %s, want %s", got.body, want)
+	}
+	if got.event != "created" {
+		t.Errorf("%s = %q, want created", HeaderEvent, got.event)
+	}
+	if !Verify("s3cret", got.body, got.signature) {
+		t.Errorf("signature %q does not match the body", got.signature)
+	}
+
+	pending, err := q.Pending()
+	if err != nil || len(pending) != 0 {
+		t.Errorf("Pending() = %d deliveries, %v; want none left", len(pending), err)
+	}
+}
+
+func TestQueueDoesNotStoreSecret(t *testing.T) {
+	dir := t.TempDir()
+	q := NewQueue(dir)
+	if _, err := q.Enqueue("created", "http://127.0.0.1:1/hook", "s3cret", []byte(`{}`)); err != nil {
+		t.Fatalf("Enqueue() error: %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "pending", "*.json"))
+	if len(files) != 1 {
+		t.Fatalf("found %d queue files, want 1", len(files))
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "s3cret") {
+		t.Errorf("queue file contains the secret:\n%s", data)
+	}
+}
+
+func TestDeliverRetriesWithBackoff(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	q := NewQueue(t.TempDir())
+	if _, err := q.Enqueue("created", server.URL, "", []byte(`{}`)); err != nil {
+		t.Fatalf("Enqueue() error: %v", err)
+	}
+
+	now := time.Now()
+	results, err := q.Deliver(server.Client(), now)
+	if err != nil {
+		t.Fatalf("Deliver() error: %v", err)
+	}
+	if len(results) != 1 || results[0].Err == nil || results[0].Dropped {
+		t.Fatalf("first Deliver() results = %+v, want one retryable failure", results)
+	}
+
+	pending, _ := q.Pending()
+	if len(pending) != 1 {
+		t.Fatalf("Pending() = %d deliveries, want 1", len(pending))
+	}
+	if d := pending[0]; d.Attempts != 1 || !d.NextAttempt.Equal(now.Add(BaseDelay)) {
+		t.Errorf("after a failure: attempts %d, next attempt %v; want 1, %v", d.Attempts, d.NextAttempt, now.Add(BaseDelay))
+	}
+
+	// Nothing is sent before the retry is due
+	if results, _ := q.Deliver(server.Client(), now.Add(BaseDelay/2)); len(results) != 0 {
+		t.Errorf("Deliver() before the retry is due sent %d deliveries", len(results))
+	}
+
+	results, err = q.Deliver(server.Client(), now.Add(BaseDelay))
+	if err != nil || len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("retry results = %+v, %v; want one success", results, err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("endpoint called %d times, want 2", calls.Load())
+	}
+}
+
+func TestDeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	q := NewQueue(t.TempDir())
+	if _, err := q.Enqueue("created", server.URL, "", []byte(`{}`)); err != nil {
+		t.Fatalf("Enqueue() error: %v", err)
+	}
+
+	now := time.Now()
+	var last []Result
+	for i := 0; i < MaxAttempts; i++ {
+		results, err := q.Deliver(server.Client(), now)
+		if err != nil {
+			t.Fatalf("Deliver() error: %v", err)
+		}
+		last = results
+		now = now.Add(MaxDelay)
+	}
+
+	if len(last) != 1 || !last[0].Dropped {
+		t.Fatalf("last Deliver() results = %+v, want the delivery dropped", last)
+	}
+	if pending, _ := q.Pending(); len(pending) != 0 {
+		t.Errorf("Pending() = %d deliveries, want none", len(pending))
+	}
+	if failed, _ := q.Failed(); len(failed) != 1 {
+		t.Errorf("Failed() = %d deliveries, want 1", len(failed))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, BaseDelay},
+		{2, 2 * BaseDelay},
+		{3, 4 * BaseDelay},
+		{20, MaxDelay},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempts); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}